@@ -0,0 +1,338 @@
+package diadocсlient
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// headDepartmentName наименование головного подразделения в отчетах
+const headDepartmentName = "Головное подразделение"
+
+// AccessAuditRow права сотрудника в одном ящике
+type AccessAuditRow struct {
+	OrgID               string          `json:"orgId"`
+	Inn                 string          `json:"inn"`
+	OrgName             string          `json:"orgName"`
+	BoxID               string          `json:"boxId"`
+	BoxTitle            string          `json:"boxTitle"`
+	UserID              string          `json:"userId"`
+	Login               string          `json:"login"`
+	FullName            string          `json:"fullName"`
+	Position            string          `json:"position"`
+	DepartmentID        string          `json:"departmentId"`
+	DepartmentName      string          `json:"departmentName"`
+	DocumentAccessLevel string          `json:"documentAccessLevel"`
+	SelectedDepartments []string        `json:"selectedDepartments,omitempty"`
+	IsAdministrator     bool            `json:"isAdministrator"`
+	IsBlocked           bool            `json:"isBlocked"`
+	Actions             map[string]bool `json:"actions"`
+	Subscriptions       []string        `json:"subscriptions,omitempty"`
+}
+
+// AccessAuditSkip ящик, который не удалось проверить (например, текущий пользователь не администратор ящика)
+type AccessAuditSkip struct {
+	BoxID string `json:"boxId"`
+	Error string `json:"error"`
+}
+
+// AccessAuditReport снимок прав сотрудников во всех ящиках текущего пользователя
+type AccessAuditReport struct {
+	GeneratedAt time.Time         `json:"generatedAt"`
+	Rows        []AccessAuditRow  `json:"rows"`
+	Skipped     []AccessAuditSkip `json:"skipped,omitempty"`
+}
+
+// AuditEmployeeAccess обходит все ящики организаций GetMyOrganizations и собирает права сотрудников:
+// подразделение, уровень доступа к документам, действия, флаг администратора и подписки на уведомления.
+// Ящики, доступ к сотрудникам или подпискам которых запрещен (текущий пользователь не администратор),
+// попадают в Skipped; остальные ошибки прерывают аудит
+func (c DiadocClient) AuditEmployeeAccess(ctx context.Context) (*AccessAuditReport, error) {
+	organizations, err := c.GetMyOrganizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &AccessAuditReport{GeneratedAt: time.Now().UTC()}
+	for _, organization := range organizations.Organizations {
+		departments := map[string]string{headDepartmentID: headDepartmentName}
+		for _, department := range organization.Departments {
+			departments[department.GetDepartmentId()] = department.GetName()
+		}
+		for _, box := range organization.Boxes {
+			rows, err := c.auditBox(ctx, organization, box, departments)
+			if err != nil {
+				if !isAccessDenied(err) {
+					return nil, err
+				}
+				report.Skipped = append(report.Skipped, AccessAuditSkip{BoxID: box.GetBoxId(), Error: err.Error()})
+				continue
+			}
+			report.Rows = append(report.Rows, rows...)
+		}
+	}
+	return report, nil
+}
+
+// auditBox собирает права сотрудников одного ящика
+func (c DiadocClient) auditBox(ctx context.Context, organization *model.Organization, box *model.Box, departments map[string]string) ([]AccessAuditRow, error) {
+	employees, err := c.ListAllEmployees(ctx, box.GetBoxId())
+	if err != nil {
+		return nil, err
+	}
+	var rows []AccessAuditRow
+	for _, employee := range employees {
+		subscriptions, err := c.GetSubscriptions(ctx, box.GetBoxId(), employee.GetUser().GetUserId())
+		if err != nil {
+			return nil, err
+		}
+		row := auditRow(organization, box, employee, departments)
+		for _, subscription := range subscriptions.GetSubscriptions() {
+			if subscription.GetIsSubscribed() {
+				row.Subscriptions = append(row.Subscriptions, subscription.GetId())
+			}
+		}
+		sort.Strings(row.Subscriptions)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// isAccessDenied ошибка 403: доступ к ящику запрещен или запрос сделан не от имени администратора
+func isAccessDenied(err error) bool {
+	return strings.HasPrefix(err.Error(), "{403}")
+}
+
+func auditRow(organization *model.Organization, box *model.Box, employee *model.Employee, departments map[string]string) AccessAuditRow {
+	user := employee.GetUser()
+	permissions := employee.GetPermissions()
+	departmentName := func(id string) string {
+		if id == "" {
+			id = headDepartmentID
+		}
+		if name, ok := departments[id]; ok {
+			return name
+		}
+		return id
+	}
+	row := AccessAuditRow{
+		OrgID:               organization.GetOrgId(),
+		Inn:                 organization.GetInn(),
+		OrgName:             organization.GetShortName(),
+		BoxID:               box.GetBoxId(),
+		BoxTitle:            box.GetTitle(),
+		UserID:              user.GetUserId(),
+		Login:               user.GetLogin(),
+		FullName:            formatFullName(user.GetFullName()),
+		Position:            employee.GetPosition(),
+		DepartmentID:        permissions.GetUserDepartmentId(),
+		DepartmentName:      departmentName(permissions.GetUserDepartmentId()),
+		DocumentAccessLevel: permissions.GetDocumentAccessLevel().String(),
+		IsAdministrator:     permissions.GetIsAdministrator(),
+		IsBlocked:           permissions.GetAuthorizationPermission().GetIsBlocked(),
+		Actions:             make(map[string]bool),
+	}
+	if row.OrgName == "" {
+		row.OrgName = organization.GetFullName()
+	}
+	for _, id := range permissions.GetSelectedDepartmentIds() {
+		row.SelectedDepartments = append(row.SelectedDepartments, departmentName(id))
+	}
+	sort.Strings(row.SelectedDepartments)
+	for _, action := range permissions.GetActions() {
+		row.Actions[action.GetName()] = action.GetIsAllowed()
+	}
+	return row
+}
+
+func formatFullName(name *model.FullName) string {
+	var parts []string
+	for _, part := range []string{name.GetLastName(), name.GetFirstName(), name.GetMiddleName()} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// actionNames объединение имен действий всех строк в алфавитном порядке
+func (r *AccessAuditReport) actionNames() []string {
+	names := make(map[string]bool)
+	for _, row := range r.Rows {
+		for name := range row.Actions {
+			names[name] = true
+		}
+	}
+	return sortedKeys(names)
+}
+
+// WriteCSV выводит отчет в виде матрицы: по строке на сотрудника в ящике, по столбцу на каждое действие
+func (r *AccessAuditReport) WriteCSV(w io.Writer) error {
+	actions := r.actionNames()
+	header := []string{"inn", "org_name", "box_id", "box_title", "user_id", "login", "full_name", "position",
+		"department", "document_access_level", "selected_departments", "is_administrator", "is_blocked", "subscriptions"}
+	for _, action := range actions {
+		header = append(header, "action:"+action)
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		record := []string{row.Inn, row.OrgName, row.BoxID, row.BoxTitle, row.UserID, row.Login, row.FullName, row.Position,
+			row.DepartmentName, row.DocumentAccessLevel, strings.Join(row.SelectedDepartments, "; "),
+			strconv.FormatBool(row.IsAdministrator), strconv.FormatBool(row.IsBlocked), strings.Join(row.Subscriptions, "; ")}
+		for _, action := range actions {
+			record = append(record, strconv.FormatBool(row.Actions[action]))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON выводит отчет в JSON; этот же формат читает ReadAccessAuditReport
+func (r *AccessAuditReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// ReadAccessAuditReport читает отчет, сохраненный WriteJSON
+func ReadAccessAuditReport(r io.Reader) (*AccessAuditReport, error) {
+	report := &AccessAuditReport{}
+	if err := json.NewDecoder(r).Decode(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+type AccessChangeKind string
+
+const (
+	AccessAdded   AccessChangeKind = "added"
+	AccessRemoved AccessChangeKind = "removed"
+	AccessChanged AccessChangeKind = "changed"
+)
+
+// AccessChange отличие прав сотрудника в ящике между двумя снимками
+type AccessChange struct {
+	Kind     AccessChangeKind `json:"kind"`
+	BoxID    string           `json:"boxId"`
+	BoxTitle string           `json:"boxTitle"`
+	UserID   string           `json:"userId"`
+	Login    string           `json:"login"`
+	Changes  []string         `json:"changes,omitempty"`
+}
+
+// AccessAuditDiff результат сравнения двух снимков
+type AccessAuditDiff struct {
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	Changes []AccessChange `json:"changes"`
+}
+
+// DiffAccessAudit сравнивает два снимка. Сотрудник определяется парой ящик + UserID
+func DiffAccessAudit(before *AccessAuditReport, after *AccessAuditReport) *AccessAuditDiff {
+	key := func(row AccessAuditRow) string {
+		return row.BoxID + "/" + row.UserID
+	}
+	previous := make(map[string]AccessAuditRow, len(before.Rows))
+	for _, row := range before.Rows {
+		previous[key(row)] = row
+	}
+	diff := &AccessAuditDiff{From: before.GeneratedAt, To: after.GeneratedAt}
+	seen := make(map[string]bool, len(after.Rows))
+	for _, row := range after.Rows {
+		seen[key(row)] = true
+		old, ok := previous[key(row)]
+		if !ok {
+			diff.Changes = append(diff.Changes, accessChange(AccessAdded, row, nil))
+			continue
+		}
+		if changes := compareAccess(old, row); len(changes) > 0 {
+			diff.Changes = append(diff.Changes, accessChange(AccessChanged, row, changes))
+		}
+	}
+	for _, row := range before.Rows {
+		if !seen[key(row)] {
+			diff.Changes = append(diff.Changes, accessChange(AccessRemoved, row, nil))
+		}
+	}
+	return diff
+}
+
+func accessChange(kind AccessChangeKind, row AccessAuditRow, changes []string) AccessChange {
+	return AccessChange{Kind: kind, BoxID: row.BoxID, BoxTitle: row.BoxTitle, UserID: row.UserID, Login: row.Login, Changes: changes}
+}
+
+func compareAccess(before AccessAuditRow, after AccessAuditRow) []string {
+	var changes []string
+	compare := func(name string, from string, to string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s: %q -> %q", name, from, to))
+		}
+	}
+	compare("position", before.Position, after.Position)
+	compare("department", before.DepartmentName, after.DepartmentName)
+	compare("documentAccessLevel", before.DocumentAccessLevel, after.DocumentAccessLevel)
+	compare("selectedDepartments", strings.Join(before.SelectedDepartments, "; "), strings.Join(after.SelectedDepartments, "; "))
+	compare("isAdministrator", strconv.FormatBool(before.IsAdministrator), strconv.FormatBool(after.IsAdministrator))
+	compare("isBlocked", strconv.FormatBool(before.IsBlocked), strconv.FormatBool(after.IsBlocked))
+	compare("subscriptions", strings.Join(before.Subscriptions, "; "), strings.Join(after.Subscriptions, "; "))
+	names := make(map[string]bool)
+	for name := range before.Actions {
+		names[name] = true
+	}
+	for name := range after.Actions {
+		names[name] = true
+	}
+	for _, name := range sortedKeys(names) {
+		compare("action "+name, strconv.FormatBool(before.Actions[name]), strconv.FormatBool(after.Actions[name]))
+	}
+	return changes
+}
+
+// String выводит изменения в читаемом виде
+func (d *AccessAuditDiff) String() string {
+	if len(d.Changes) == 0 {
+		return "Изменений нет\n"
+	}
+	var buf strings.Builder
+	for _, change := range d.Changes {
+		fmt.Fprintf(&buf, "%s %s (%s) %s\n", change.Kind, change.BoxTitle, change.BoxID, change.Login)
+		for _, line := range change.Changes {
+			fmt.Fprintf(&buf, "    %s\n", line)
+		}
+	}
+	return buf.String()
+}
+
+// WriteCSV выводит изменения по строке на каждое отличие
+func (d *AccessAuditDiff) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"kind", "box_id", "box_title", "user_id", "login", "change"}); err != nil {
+		return err
+	}
+	for _, change := range d.Changes {
+		lines := change.Changes
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
+		for _, line := range lines {
+			if err := writer.Write([]string{string(change.Kind), change.BoxID, change.BoxTitle, change.UserID, change.Login, line}); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,270 @@
+package diadocсlient
+
+import (
+	"context"
+	"github.com/DimaSSV/diadocclient/pkg/cache"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"google.golang.org/protobuf/proto"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CachedLookup кэширует справочные запросы по организациям, ящикам и контрагентам.
+// Ответы хранятся в Backend в виде сериализованных сообщений и считаются актуальными в течение ttl
+type CachedLookup struct {
+	client  DiadocClient
+	backend cache.Backend
+	ttl     time.Duration
+}
+
+func (c DiadocClient) NewCachedLookup(backend cache.Backend, ttl time.Duration) *CachedLookup {
+	return &CachedLookup{
+		client:  c,
+		backend: backend,
+		ttl:     ttl,
+	}
+}
+
+func (l *CachedLookup) GetBox(ctx context.Context, boxID string) (*model.Box, error) {
+	return cached(l, "box:"+boxID, &model.Box{}, func() (*model.Box, error) {
+		return l.client.GetBox(ctx, boxID)
+	})
+}
+
+func (l *CachedLookup) GetOrganizationByOrgID(ctx context.Context, orgID string) (*model.Organization, error) {
+	return cached(l, "org:id:"+orgID, &model.Organization{}, func() (*model.Organization, error) {
+		return l.client.GetOrganizationByOrgID(ctx, orgID)
+	})
+}
+
+func (l *CachedLookup) GetOrganizationByBoxID(ctx context.Context, boxID string) (*model.Organization, error) {
+	return cached(l, "org:box:"+boxID, &model.Organization{}, func() (*model.Organization, error) {
+		return l.client.GetOrganizationByBoxID(ctx, boxID)
+	})
+}
+
+func (l *CachedLookup) GetOrganizationByINN(ctx context.Context, INN string, KPP string) (*model.Organization, error) {
+	return cached(l, "org:inn:"+INN+":"+KPP, &model.Organization{}, func() (*model.Organization, error) {
+		return l.client.GetOrganizationByINN(ctx, INN, KPP)
+	})
+}
+
+func (l *CachedLookup) GetOrganizationsByInnKpp(ctx context.Context, INN string, KPP string, includeRelations bool) (*model.OrganizationList, error) {
+	key := "orgs:inn:" + INN + ":" + KPP + ":" + strconv.FormatBool(includeRelations)
+	return cached(l, key, &model.OrganizationList{}, func() (*model.OrganizationList, error) {
+		return l.client.GetOrganizationsByInnKpp(ctx, INN, KPP, includeRelations)
+	})
+}
+
+// GetOrganizationsByInnList кэширует ответ по каждому ИНН отдельно и запрашивает только отсутствующие в кэше ИНН.
+// ИНН, по которым организации не найдены, также кэшируются
+func (l *CachedLookup) GetOrganizationsByInnList(ctx context.Context, myOrgID string, INNs []string) (*model.GetOrganizationsByInnListResponse, error) {
+	result := &model.GetOrganizationsByInnListResponse{}
+	var missing []string
+	for _, inn := range INNs {
+		entry := &model.GetOrganizationsByInnListResponse{}
+		if l.get(innListKey(myOrgID, inn), entry) {
+			result.Organizations = append(result.Organizations, entry.Organizations...)
+		} else {
+			missing = append(missing, inn)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+	response, err := l.client.GetOrganizationsByInnList(ctx, myOrgID, missing)
+	if err != nil {
+		return nil, err
+	}
+	byInn := make(map[string]*model.GetOrganizationsByInnListResponse, len(missing))
+	for _, inn := range missing {
+		byInn[inn] = &model.GetOrganizationsByInnListResponse{}
+	}
+	for _, organization := range response.Organizations {
+		if entry, ok := byInn[organization.GetOrganization().GetInn()]; ok {
+			entry.Organizations = append(entry.Organizations, organization)
+		}
+	}
+	for inn, entry := range byInn {
+		l.set(innListKey(myOrgID, inn), entry)
+	}
+	result.Organizations = append(result.Organizations, response.Organizations...)
+	return result, nil
+}
+
+func (l *CachedLookup) GetCounteragent(ctx context.Context, myOrgID string, counteragentOrgID string) (*model.Counteragent, error) {
+	return cached(l, counteragentKey(myOrgID, counteragentOrgID), &model.Counteragent{}, func() (*model.Counteragent, error) {
+		return l.client.GetCounteragentV2(ctx, myOrgID, counteragentOrgID)
+	})
+}
+
+// Invalidate удаляет запись о контрагенте, например после приглашения или разрыва отношений
+func (l *CachedLookup) Invalidate(myOrgID string, counteragentOrgID string) {
+	l.backend.Delete(counteragentKey(myOrgID, counteragentOrgID))
+}
+
+func (l *CachedLookup) get(key string, m proto.Message) bool {
+	data, ok := l.backend.Get(key)
+	if !ok {
+		return false
+	}
+	return proto.Unmarshal(data, m) == nil
+}
+
+func (l *CachedLookup) set(key string, m proto.Message) {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return
+	}
+	l.backend.Set(key, data, l.ttl)
+}
+
+func cached[T proto.Message](l *CachedLookup, key string, empty T, load func() (T, error)) (T, error) {
+	if l.get(key, empty) {
+		return empty, nil
+	}
+	result, err := load()
+	if err != nil {
+		return result, err
+	}
+	l.set(key, result)
+	return result, nil
+}
+
+func innListKey(myOrgID string, inn string) string {
+	return "innlist:" + myOrgID + ":" + inn
+}
+
+func counteragentKey(myOrgID string, counteragentOrgID string) string {
+	return "counteragent:" + myOrgID + ":" + counteragentOrgID
+}
+
+// CounteragentChange изменение контрагента, обнаруженное при синхронизации
+type CounteragentChange struct {
+	OrgID string
+	// Previous равен nil для нового контрагента
+	Previous *model.Counteragent
+	// Current равен nil, если контрагент пропал из списка
+	Current       *model.Counteragent
+	StatusChanged bool
+	BoxesChanged  bool
+}
+
+// counteragentSyncInterval период синхронизации контрагентов, если interval не задан
+const counteragentSyncInterval = time.Hour
+
+// CounteragentSync периодически загружает полный список контрагентов (GetCounteragentsV2), обновляет им кэш
+// и сообщает об изменениях статуса и ящиков контрагентов
+type CounteragentSync struct {
+	lookup   *CachedLookup
+	manager  *CounteragentManager
+	orgID    string
+	interval time.Duration
+
+	mu       sync.Mutex
+	known    map[string]*model.Counteragent
+	handlers []func(CounteragentChange)
+}
+
+// NewCounteragentSync создает синхронизацию списка контрагентов организации myOrgID с периодом interval (0 - час)
+func (l *CachedLookup) NewCounteragentSync(myOrgID string, interval time.Duration) *CounteragentSync {
+	if interval <= 0 {
+		interval = counteragentSyncInterval
+	}
+	return &CounteragentSync{
+		lookup:   l,
+		manager:  l.client.NewCounteragentManager(myOrgID, ""),
+		orgID:    myOrgID,
+		interval: interval,
+	}
+}
+
+// OnChange регистрирует обработчик изменений. При первой синхронизации все контрагенты считаются новыми
+func (s *CounteragentSync) OnChange(handler func(CounteragentChange)) *CounteragentSync {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = append(s.handlers, handler)
+	return s
+}
+
+// Run выполняет синхронизацию до отмены контекста. Ошибка отдельной синхронизации не прерывает работу
+// и передается в onError, если он задан
+func (s *CounteragentSync) Run(ctx context.Context, onError func(error)) error {
+	for {
+		if _, err := s.Sync(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+// Sync загружает список контрагентов один раз, обновляет кэш и возвращает обнаруженные изменения
+func (s *CounteragentSync) Sync(ctx context.Context) ([]CounteragentChange, error) {
+	counteragents, err := s.manager.List(ctx, model.CounteragentStatus_UnknownCounteragentStatus)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]*model.Counteragent, len(counteragents))
+	for _, counteragent := range counteragents {
+		orgID := counteragent.GetOrganization().GetOrgId()
+		current[orgID] = counteragent
+		s.lookup.set(counteragentKey(s.orgID, orgID), counteragent)
+	}
+
+	s.mu.Lock()
+	var changes []CounteragentChange
+	for orgID, counteragent := range current {
+		previous := s.known[orgID]
+		change := CounteragentChange{
+			OrgID:         orgID,
+			Previous:      previous,
+			Current:       counteragent,
+			StatusChanged: previous.GetCurrentStatus() != counteragent.GetCurrentStatus(),
+			BoxesChanged:  !sameBoxes(previous.GetOrganization(), counteragent.GetOrganization()),
+		}
+		if previous == nil || change.StatusChanged || change.BoxesChanged {
+			changes = append(changes, change)
+		}
+	}
+	for orgID, previous := range s.known {
+		if _, ok := current[orgID]; !ok {
+			s.lookup.Invalidate(s.orgID, orgID)
+			changes = append(changes, CounteragentChange{OrgID: orgID, Previous: previous, StatusChanged: true, BoxesChanged: true})
+		}
+	}
+	s.known = current
+	handlers := s.handlers
+	s.mu.Unlock()
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].OrgID < changes[j].OrgID
+	})
+	for _, change := range changes {
+		for _, handler := range handlers {
+			handler(change)
+		}
+	}
+	return changes, nil
+}
+
+func sameBoxes(a *model.Organization, b *model.Organization) bool {
+	if len(a.GetBoxes()) != len(b.GetBoxes()) {
+		return false
+	}
+	ids := make(map[string]bool, len(a.GetBoxes()))
+	for _, box := range a.GetBoxes() {
+		ids[box.GetBoxId()] = true
+	}
+	for _, box := range b.GetBoxes() {
+		if !ids[box.GetBoxId()] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,207 @@
+package diadocсlient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DimaSSV/diadocclient/pkg/credentials"
+	"github.com/DimaSSV/diadocclient/pkg/ratelimit"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// unknownRouteTTL время, в течение которого неизвестный ящик или организация не вызывают повторный Discover
+const unknownRouteTTL = 5 * time.Minute
+
+// Account учетные данные одного пользователя Диадока в пуле
+type Account struct {
+	// Name имя учетной записи в пуле
+	Name         string
+	Login        string
+	Password     string
+	ClientID     string
+	InitialToken string
+	// Credentials источник учетных данных; если задан, Login, Password и ClientID не используются
+	Credentials credentials.Provider
+}
+
+// ClientPool набор клиентов для нескольких учетных записей с общим транспортом и ограничением частоты запросов.
+// Запросы направляются клиенту, которому доступен ящик или организация (по данным GetMyOrganizations)
+type ClientPool struct {
+	transport http.RoundTripper
+	limiter   *ratelimit.Limiter
+
+	mu      sync.RWMutex
+	names   []string
+	clients map[string]DiadocClient
+	// boxes и orgs сопоставляют идентификатор ящика (в обоих форматах) и организации с именем учетной записи
+	boxes map[string]string
+	orgs  map[string]string
+	// unknown время последнего безуспешного поиска ящика или организации после Discover
+	unknown map[string]time.Time
+}
+
+// NewClientPool создает пул. transport и limiter используются всеми клиентами пула; nil - транспорт
+// по умолчанию и без ограничения частоты
+func NewClientPool(transport http.RoundTripper, limiter *ratelimit.Limiter) *ClientPool {
+	return &ClientPool{
+		transport: transport,
+		limiter:   limiter,
+		clients:   make(map[string]DiadocClient),
+		boxes:     make(map[string]string),
+		orgs:      make(map[string]string),
+		unknown:   make(map[string]time.Time),
+	}
+}
+
+// Add создает клиента для учетной записи и добавляет его в пул
+func (p *ClientPool) Add(account Account) (DiadocClient, error) {
+	if account.Name == "" {
+		account.Name = account.Login
+	}
+	if account.Name == "" {
+		return DiadocClient{}, errors.New("не указано имя учетной записи")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, exists := p.clients[account.Name]; exists {
+		return DiadocClient{}, fmt.Errorf("учетная запись %s уже добавлена в пул", account.Name)
+	}
+	var client DiadocClient
+	if account.Credentials != nil {
+		client = NewWithCredentials(account.Credentials, account.InitialToken)
+	} else {
+		var err error
+		if client, err = New(account.Login, account.Password, account.ClientID, account.InitialToken); err != nil {
+			return DiadocClient{}, fmt.Errorf("%s: %w", account.Name, err)
+		}
+	}
+	client.SetTransport(p.transport)
+	client.SetRateLimiter(p.limiter)
+	p.names = append(p.names, account.Name)
+	p.clients[account.Name] = client
+	// Новой учетной записи могут быть доступны ящики, которые раньше не нашлись
+	p.unknown = make(map[string]time.Time)
+	return client, nil
+}
+
+// Client возвращает клиента учетной записи по имени
+func (p *ClientPool) Client(name string) (DiadocClient, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	client, ok := p.clients[name]
+	return client, ok
+}
+
+// Accounts возвращает имена учетных записей в порядке добавления
+func (p *ClientPool) Accounts() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]string{}, p.names...)
+}
+
+// Discover запрашивает GetMyOrganizations для каждой учетной записи и запоминает, кому доступны
+// ящики и организации. Если ящик доступен нескольким учетным записям, используется добавленная раньше
+func (p *ClientPool) Discover(ctx context.Context) error {
+	boxes := make(map[string]string)
+	orgs := make(map[string]string)
+	for _, name := range p.Accounts() {
+		client, _ := p.Client(name)
+		organizations, err := client.GetMyOrganizations(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		for _, organization := range organizations.Organizations {
+			setIfAbsent(orgs, organization.GetOrgId(), name)
+			for _, box := range organization.Boxes {
+				setIfAbsent(boxes, normalizeBoxID(box.GetBoxId()), name)
+				setIfAbsent(boxes, normalizeBoxID(box.GetBoxIdGuid()), name)
+			}
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.boxes = boxes
+	p.orgs = orgs
+	p.unknown = make(map[string]time.Time)
+	return nil
+}
+
+// ForBox возвращает клиента, которому доступен ящик. Если ящик неизвестен, состав ящиков запрашивается заново
+// (не чаще раза в 5 минут для одного и того же ящика)
+func (p *ClientPool) ForBox(ctx context.Context, boxID string) (DiadocClient, error) {
+	key := normalizeBoxID(boxID)
+	return p.route(ctx, "box:"+key, func() (string, bool) {
+		name, ok := p.boxes[key]
+		return name, ok
+	}, "ящик "+boxID)
+}
+
+// ForOrg возвращает клиента, которому доступна организация
+func (p *ClientPool) ForOrg(ctx context.Context, orgID string) (DiadocClient, error) {
+	return p.route(ctx, "org:"+orgID, func() (string, bool) {
+		name, ok := p.orgs[orgID]
+		return name, ok
+	}, "организация "+orgID)
+}
+
+// route ищет учетную запись; lookup вызывается под блокировкой пула. Неудачный поиск после Discover
+// запоминается по key на unknownRouteTTL, чтобы запросы к чужим ящикам не вызывали Discover каждый раз
+func (p *ClientPool) route(ctx context.Context, key string, lookup func() (string, bool), what string) (DiadocClient, error) {
+	notFound := fmt.Errorf("%s недоступна ни одной учетной записи пула", what)
+	for attempt := 0; attempt < 2; attempt++ {
+		if attempt > 0 {
+			if err := p.Discover(ctx); err != nil {
+				return DiadocClient{}, err
+			}
+		}
+		p.mu.RLock()
+		name, ok := lookup()
+		client := p.clients[name]
+		missed, known := p.unknown[key]
+		p.mu.RUnlock()
+		if ok {
+			return client, nil
+		}
+		if attempt == 0 && known && time.Since(missed) < unknownRouteTTL {
+			return DiadocClient{}, notFound
+		}
+	}
+	p.mu.Lock()
+	p.unknown[key] = time.Now()
+	p.mu.Unlock()
+	return DiadocClient{}, notFound
+}
+
+// RefreshTokens заново получает токены всех учетных записей. Ошибка одной учетной записи
+// не прерывает обновление остальных, сообщения об ошибках объединяются
+func (p *ClientPool) RefreshTokens(ctx context.Context) error {
+	var messages []string
+	for _, name := range p.Accounts() {
+		client, _ := p.Client(name)
+		if err := client.RefreshToken(ctx); err != nil {
+			messages = append(messages, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+	if len(messages) > 0 {
+		return errors.New(strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+func setIfAbsent(m map[string]string, key string, value string) {
+	if _, ok := m[key]; key != "" && !ok {
+		m[key] = value
+	}
+}
+
+// normalizeBoxID приводит идентификатор ящика к виду без домена: "guid@diadoc.ru" и "guid" совпадают
+func normalizeBoxID(boxID string) string {
+	boxID = strings.ToLower(boxID)
+	if i := strings.Index(boxID, "@"); i >= 0 {
+		boxID = boxID[:i]
+	}
+	return strings.ReplaceAll(boxID, "-", "")
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/DimaSSV/diadocclient/internal/service/document"
+	"github.com/DimaSSV/diadocclient/pkg/filter"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"github.com/DimaSSV/diadocclient/pkg/ticks"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout формат дат во флагах команд
+const dateLayout = "2006-01-02"
+
+func runOrgs(ctx context.Context, app *app, args []string) error {
+	if err := app.newFlagSet("orgs").Parse(args); err != nil {
+		return err
+	}
+	organizations, err := app.client.GetMyOrganizations(ctx)
+	if err != nil {
+		return err
+	}
+	t := &table{header: []string{"ORG ID", "ИНН", "КПП", "НАИМЕНОВАНИЕ", "ЯЩИКОВ"}}
+	for _, organization := range organizations.Organizations {
+		t.add(organization.GetOrgId(), organization.GetInn(), organization.GetKpp(), organizationName(organization),
+			strconv.Itoa(len(organization.Boxes)))
+	}
+	return app.out.print(organizations, t)
+}
+
+func runBoxes(ctx context.Context, app *app, args []string) error {
+	flags := app.newFlagSet("boxes")
+	orgID := flags.String("org", "", "только ящики организации")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	organizations, err := app.client.GetMyOrganizations(ctx)
+	if err != nil {
+		return err
+	}
+	result := &model.OrganizationList{}
+	t := &table{header: []string{"BOX ID", "НАЗВАНИЕ", "ИНН", "ОРГАНИЗАЦИЯ"}}
+	for _, organization := range organizations.Organizations {
+		if *orgID != "" && organization.GetOrgId() != *orgID {
+			continue
+		}
+		result.Organizations = append(result.Organizations, organization)
+		for _, box := range organization.Boxes {
+			t.add(box.GetBoxId(), box.GetTitle(), organization.GetInn(), organizationName(organization))
+		}
+	}
+	return app.out.print(result, t)
+}
+
+func runDocsList(ctx context.Context, app *app, args []string) error {
+	flags := app.newFlagSet("docs list")
+	boxID := flags.String("box", "", "идентификатор ящика")
+	category := flags.String("category", string(filter.NewCategory(filter.AnyDocumentType, filter.Inbound)), "категория <тип документа>.<статус>")
+	counteragent := flags.String("counteragent", "", "ящик контрагента")
+	from := flags.String("from", "", "начало периода по времени документа, "+dateLayout)
+	to := flags.String("to", "", "окончание периода по времени документа, "+dateLayout)
+	docFrom := flags.String("doc-from", "", "начало периода по дате документа, "+dateLayout)
+	docTo := flags.String("doc-to", "", "окончание периода по дате документа, "+dateLayout)
+	number := flags.String("number", "", "номер документа")
+	department := flags.String("department", "", "подразделение")
+	after := flags.String("after", "", "продолжить после документа с этим IndexKey")
+	sortDirection := flags.String("sort", "", "порядок сортировки: Ascending или Descending")
+	count := flags.Int("count", 100, "количество документов (до 100)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"box": *boxID}); err != nil {
+		return err
+	}
+	f := document.NewFilter(*boxID, *after)
+	f.FilterCategory = filter.Category(*category)
+	f.CounteragentBoxID = *counteragent
+	f.DocumentNumber = *number
+	f.DepartmentID = *department
+	f.SortDirection = filter.SortDirection(*sortDirection)
+	f.Count = *count
+	var err error
+	if f.TimestampFromTicks, err = parseTicks(*from, false); err != nil {
+		return err
+	}
+	if f.TimestampToTicks, err = parseTicks(*to, true); err != nil {
+		return err
+	}
+	if f.FromDocumentDate, err = parseDate(*docFrom); err != nil {
+		return err
+	}
+	if f.ToDocumentDate, err = parseDate(*docTo); err != nil {
+		return err
+	}
+	documents, err := app.client.GetDocuments(ctx, f)
+	if err != nil {
+		return err
+	}
+	t := &table{header: []string{"ВРЕМЯ", "ТИП", "НОМЕР", "ДАТА", "КОНТРАГЕНТ", "MESSAGE ID", "ENTITY ID"}}
+	for _, doc := range documents.Documents {
+		t.add(formatTicks(doc.GetCreationTimestampTicks()), doc.GetTypeNamedId(), doc.GetDocumentNumber(), doc.GetDocumentDate(),
+			doc.GetCounteragentBoxId(), doc.GetMessageId(), doc.GetEntityId())
+	}
+	if err = app.out.print(documents, t); err != nil {
+		return err
+	}
+	if app.out.format == formatTable && documents.GetHasMoreResults() && len(documents.Documents) > 0 {
+		fmt.Fprintf(app.stderr, "Показано %d из %d, продолжение: -after %s\n", len(documents.Documents), documents.GetTotalCount(),
+			documents.Documents[len(documents.Documents)-1].GetIndexKey())
+	}
+	return nil
+}
+
+func runDocGet(ctx context.Context, app *app, args []string) error {
+	flags := app.newFlagSet("doc get")
+	boxID := flags.String("box", "", "идентификатор ящика")
+	messageID := flags.String("message", "", "идентификатор сообщения")
+	entityID := flags.String("entity", "", "идентификатор документа (сущности)")
+	content := flags.Bool("content", false, "включить содержимое документа")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"box": *boxID, "message": *messageID, "entity": *entityID}); err != nil {
+		return err
+	}
+	doc, err := app.client.GetDocument(ctx, *boxID, *messageID, *entityID, *content)
+	if err != nil {
+		return err
+	}
+	t := &table{header: []string{"ПОЛЕ", "ЗНАЧЕНИЕ"}}
+	t.add("Тип", doc.GetTypeNamedId())
+	t.add("Функция", doc.GetFunction())
+	t.add("Версия", doc.GetVersion())
+	t.add("Название", doc.GetTitle())
+	t.add("Номер", doc.GetDocumentNumber())
+	t.add("Дата", doc.GetDocumentDate())
+	t.add("Файл", doc.GetFileName())
+	t.add("Направление", doc.GetDocumentDirection().String())
+	t.add("Контрагент", doc.GetCounteragentBoxId())
+	t.add("Создан", formatTicks(doc.GetCreationTimestampTicks()))
+	t.add("Удален", strconv.FormatBool(doc.GetIsDeleted()))
+	return app.out.print(doc, t)
+}
+
+func runEntityDownload(ctx context.Context, app *app, args []string) error {
+	flags := app.newFlagSet("entity download")
+	boxID := flags.String("box", "", "идентификатор ящика")
+	messageID := flags.String("message", "", "идентификатор сообщения")
+	entityID := flags.String("entity", "", "идентификатор сущности")
+	out := flags.String("o", "-", "файл для сохранения (- для стандартного вывода)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"box": *boxID, "message": *messageID, "entity": *entityID}); err != nil {
+		return err
+	}
+	data, err := app.client.GetEntityContent(ctx, *boxID, *messageID, *entityID)
+	if err != nil {
+		return err
+	}
+	return writeData(app.stdout, *out, data)
+}
+
+func runCounteragents(ctx context.Context, app *app, args []string) error {
+	flags := app.newFlagSet("counteragents")
+	orgID := flags.String("org", "", "идентификатор своей организации")
+	status := flags.String("status", "", "статус (например IsMyCounteragent, InvitesMe); по умолчанию все")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"org": *orgID}); err != nil {
+		return err
+	}
+	counteragentStatus := model.CounteragentStatus_UnknownCounteragentStatus
+	if *status != "" {
+		value, ok := model.CounteragentStatus_value[*status]
+		if !ok {
+			return fmt.Errorf("неизвестный статус контрагента %q", *status)
+		}
+		counteragentStatus = model.CounteragentStatus(value)
+	}
+	counteragents, err := app.client.NewCounteragentManager(*orgID, "").List(ctx, counteragentStatus)
+	if err != nil {
+		return err
+	}
+	t := &table{header: []string{"ORG ID", "ИНН", "КПП", "НАИМЕНОВАНИЕ", "СТАТУС", "ПОСЛЕДНЕЕ СОБЫТИЕ"}}
+	for _, counteragent := range counteragents {
+		organization := counteragent.GetOrganization()
+		t.add(organization.GetOrgId(), organization.GetInn(), organization.GetKpp(), organizationName(organization),
+			counteragent.GetCurrentStatus().String(), formatTicks(counteragent.GetLastEventTimestampTicks()))
+	}
+	total := int32(len(counteragents))
+	return app.out.print(&model.CounteragentList{
+		TotalCount:     &total,
+		Counteragents:  counteragents,
+		TotalCountType: model.TotalCountType_Equal.Enum(),
+	}, t)
+}
+
+func runEmployees(ctx context.Context, app *app, args []string) error {
+	flags := app.newFlagSet("employees")
+	boxID := flags.String("box", "", "идентификатор ящика")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"box": *boxID}); err != nil {
+		return err
+	}
+	employees, err := app.client.ListAllEmployees(ctx, *boxID)
+	if err != nil {
+		return err
+	}
+	t := &table{header: []string{"USER ID", "ЛОГИН", "ФИО", "ДОЛЖНОСТЬ", "ДОСТУП", "АДМИН"}}
+	for _, employee := range employees {
+		user := employee.GetUser()
+		name := user.GetFullName()
+		t.add(user.GetUserId(), user.GetLogin(), name.GetLastName()+" "+name.GetFirstName()+" "+name.GetMiddleName(),
+			employee.GetPosition(), employee.GetPermissions().GetDocumentAccessLevel().String(),
+			strconv.FormatBool(employee.GetPermissions().GetIsAdministrator()))
+	}
+	total := int32(len(employees))
+	return app.out.print(&model.EmployeeList{Employees: employees, TotalCount: &total}, t)
+}
+
+func runShelfUpload(ctx context.Context, app *app, args []string) error {
+	flags := app.newFlagSet("shelf upload")
+	file := flags.String("file", "", "загружаемый файл (- для стандартного ввода)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"file": *file}); err != nil {
+		return err
+	}
+	var data []byte
+	var err error
+	if *file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(*file)
+	}
+	if err != nil {
+		return err
+	}
+	name, err := app.client.ShelfUpload(ctx, data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(app.stdout, name)
+	return err
+}
+
+func runShelfDownload(ctx context.Context, app *app, args []string) error {
+	flags := app.newFlagSet("shelf download")
+	name := flags.String("name", "", "имя файла на полке")
+	out := flags.String("o", "-", "файл для сохранения (- для стандартного вывода)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"name": *name}); err != nil {
+		return err
+	}
+	data, err := app.client.ShelfDownload(ctx, *name)
+	if err != nil {
+		return err
+	}
+	return writeData(app.stdout, *out, data)
+}
+
+func runPrintForm(ctx context.Context, app *app, args []string) error {
+	flags := app.newFlagSet("print-form")
+	boxID := flags.String("box", "", "идентификатор ящика")
+	messageID := flags.String("message", "", "идентификатор сообщения")
+	documentID := flags.String("document", "", "идентификатор документа")
+	out := flags.String("o", "", "файл для сохранения (- для стандартного вывода); по умолчанию имя, предложенное Диадоком")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"box": *boxID, "message": *messageID, "document": *documentID}); err != nil {
+		return err
+	}
+	file, err := app.client.GeneratePrintForm(ctx, *boxID, *messageID, *documentID)
+	if err != nil {
+		return err
+	}
+	path := *out
+	if path == "" {
+		path = safeFileName(file.FileName, *documentID+".pdf")
+	}
+	if err = writeData(app.stdout, path, file.Content); err != nil {
+		return err
+	}
+	if path != "-" {
+		fmt.Fprintln(app.stderr, "Сохранено:", path)
+	}
+	return nil
+}
+
+// safeFileName оставляет от имени файла, предложенного сервером, только последний элемент пути,
+// чтобы файл не был записан вне текущего каталога; для пустых и служебных имен возвращает fallback
+func safeFileName(name string, fallback string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	switch name {
+	case "", ".", "..", "/", "-":
+		return fallback
+	}
+	return name
+}
+
+// writeData записывает данные в файл или, если path = "-", в стандартный вывод
+func writeData(stdout io.Writer, path string, data []byte) error {
+	if path == "-" {
+		_, err := stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func organizationName(organization *model.Organization) string {
+	if organization.GetShortName() != "" {
+		return organization.GetShortName()
+	}
+	return organization.GetFullName()
+}
+
+// parseDate разбирает дату флага (пустая строка - нулевое время)
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(dateLayout, value, time.Local)
+}
+
+// parseTicks разбирает дату флага в тики; для окончания периода берется конец дня
+func parseTicks(value string, endOfDay bool) (ticks.Ticks, error) {
+	date, err := parseDate(value)
+	if err != nil || date.IsZero() {
+		return 0, err
+	}
+	if endOfDay {
+		date = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return ticks.FromTime(date), nil
+}
+
+func formatTicks(value int64) string {
+	if value == 0 {
+		return ""
+	}
+	return ticks.Ticks(value).Time().Local().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/DimaSSV/diadocclient/pkg/async"
+	"github.com/DimaSSV/diadocclient/pkg/filter"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"google.golang.org/protobuf/encoding/protojson"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+func runEventsTail(ctx context.Context, app *app, args []string) error {
+	flags := app.newFlagSet("events tail")
+	boxID := flags.String("box", "", "идентификатор ящика")
+	after := flags.String("after", "", "продолжить после события с этим IndexKey (важнее сохраненного курсора)")
+	fromStart := flags.Bool("from-start", false, "без курсора начать с первого события ящика (по умолчанию - с последнего)")
+	cursorPath := flags.String("cursor", "", "файл курсора (по умолчанию в каталоге кэша пользователя)")
+	noCursor := flags.Bool("no-cursor", false, "не читать и не сохранять курсор")
+	interval := flags.Duration("interval", 30*time.Second, "интервал опроса при отсутствии новых событий")
+	var messageTypes, directions, typeNamedIDs listFlag
+	flags.Var(&messageTypes, "message-type", "тип сообщения: Letter, Draft, Template (можно через запятую или несколько раз)")
+	flags.Var(&directions, "direction", "направление документа: Inbound, Outbound, Internal")
+	flags.Var(&typeNamedIDs, "type", "тип документа (TypeNamedId)")
+	counteragent := flags.String("counteragent", "", "ящик контрагента")
+	department := flags.String("department", "", "подразделение")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := required(map[string]string{"box": *boxID}); err != nil {
+		return err
+	}
+	options := filter.Events{
+		DepartmentID:      *department,
+		TypeNamedIDs:      typeNamedIDs,
+		CounteragentBoxID: *counteragent,
+	}
+	for _, messageType := range messageTypes {
+		options.MessageTypes = append(options.MessageTypes, filter.MessageType(messageType))
+	}
+	for _, direction := range directions {
+		options.DocumentDirections = append(options.DocumentDirections, filter.Direction(direction))
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	cursor := cursorFile("")
+	if !*noCursor {
+		path := *cursorPath
+		if path == "" {
+			dir, err := os.UserCacheDir()
+			if err != nil {
+				return fmt.Errorf("не удалось определить каталог курсора, укажите -cursor или -no-cursor: %w", err)
+			}
+			path = filepath.Join(dir, "diadoc", "cursors", cursorName(*boxID, messageTypes, directions, typeNamedIDs, *counteragent, *department))
+		}
+		cursor = cursorFile(path)
+	}
+	afterIndexKey := *after
+	if afterIndexKey == "" {
+		stored, err := cursor.load()
+		if err != nil {
+			return err
+		}
+		afterIndexKey = stored
+	}
+	if afterIndexKey == "" && !*fromStart {
+		last, err := app.client.GetLastEvent(ctx, *boxID)
+		if err != nil {
+			return err
+		}
+		afterIndexKey = last.GetIndexKey()
+	}
+
+	for {
+		options.AfterIndexKey = afterIndexKey
+		// Временные и сетевые ошибки не прерывают tail: запрос повторяется с нарастающей паузой
+		events, err := async.Wait(ctx, async.DefaultBackoff, func(ctx context.Context) (*model.BoxEventList, async.Status, error) {
+			events, err := app.client.GetNewEvents(ctx, *boxID, options)
+			if err != nil && ctx.Err() == nil && !isClientError(err) {
+				fmt.Fprintln(app.stderr, "Ошибка получения событий, запрос будет повторен:", err)
+				return nil, async.Status{InProgress: true}, nil
+			}
+			return events, async.Status{}, err
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		for _, event := range events.Events {
+			if err = printEvent(app, event); err != nil {
+				return err
+			}
+			afterIndexKey = event.GetIndexKey()
+		}
+		if len(events.Events) > 0 {
+			// Курсор сохраняется после вывода всей пачки: при сбое события могут повториться, но не потеряются
+			if err = cursor.save(afterIndexKey); err != nil {
+				return err
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*interval):
+		}
+	}
+}
+
+// isClientError ошибка запроса {4xx}, повтор которого не поможет (кроме 429 - превышения частоты запросов)
+func isClientError(err error) bool {
+	message := err.Error()
+	return strings.HasPrefix(message, "{4") && !strings.HasPrefix(message, "{429}")
+}
+
+// cursorName имя файла курсора по умолчанию. Для фильтрованного потока к ящику добавляется хэш фильтров,
+// чтобы запуски с разными фильтрами не сдвигали курсоры друг друга
+func cursorName(boxID string, messageTypes []string, directions []string, typeNamedIDs []string, counteragent string, department string) string {
+	name := strings.ReplaceAll(boxID, "@", "_")
+	if len(messageTypes)+len(directions)+len(typeNamedIDs) == 0 && counteragent == "" && department == "" {
+		return name
+	}
+	sorted := func(values []string) string {
+		values = append([]string{}, values...)
+		sort.Strings(values)
+		return strings.Join(values, ",")
+	}
+	key := strings.Join([]string{sorted(messageTypes), sorted(directions), sorted(typeNamedIDs), counteragent, department}, "|")
+	sum := sha256.Sum256([]byte(key))
+	return name + "-" + hex.EncodeToString(sum[:6])
+}
+
+// printEvent выводит событие: строкой таблицы, строкой JSON (JSON Lines, удобно для jq) или текстом protobuf
+func printEvent(app *app, event *model.BoxEvent) error {
+	if app.out.format == formatJSON {
+		data, err := protojson.MarshalOptions{AllowPartial: true}.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(app.out.w, string(data))
+		return err
+	}
+	// Заголовок таблицы не повторяется для каждого события
+	t := &table{}
+	timestamp := event.GetMessage().GetTimestampTicks()
+	messageID := event.GetMessage().GetMessageId()
+	entities := event.GetMessage().GetEntities()
+	if event.GetPatch() != nil {
+		timestamp = event.GetPatch().GetTimestampTicks()
+		messageID = event.GetPatch().GetMessageId()
+		entities = event.GetPatch().GetEntities()
+	}
+	var kinds []string
+	for _, entity := range entities {
+		kind := entity.GetAttachmentType().String()
+		if entity.GetEntityType() != model.EntityType_TypeAttachment {
+			kind = entity.GetEntityType().String()
+		}
+		kinds = append(kinds, kind)
+	}
+	t.add(formatTicks(timestamp), event.GetEventId(), messageID, strings.Join(kinds, ", "))
+	return app.out.print(event, t)
+}
+
+// cursorFile файл с IndexKey последнего выведенного события; пустой путь - курсор не используется
+type cursorFile string
+
+func (c cursorFile) load() (string, error) {
+	if c == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(string(c))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func (c cursorFile) save(indexKey string) error {
+	if c == "" {
+		return nil
+	}
+	dir := filepath.Dir(string(c))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".cursor-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.WriteString(indexKey + "\n")
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), string(c))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// listFlag флаг со списком значений: через запятую и/или повторением флага
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+// Команда diadoc - консольный клиент Диадока для повседневных операций: просмотра организаций, ящиков,
+// документов, событий, контрагентов и сотрудников, загрузки содержимого и печатных форм.
+//
+// Учетные данные берутся из файла, указанного флагом -config (YAML или JSON с полями login, password, clientId),
+// или из переменных окружения DIADOC_LOGIN, DIADOC_PASSWORD и DIADOC_CLIENT_ID. Полученный токен
+// сохраняется в каталоге кэша пользователя, чтобы не выполнять вход при каждом запуске.
+//
+// Использование:
+//
+//	diadoc [-config файл] [-output table|json|text] команда [флаги]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	diadoc "github.com/DimaSSV/diadocclient"
+	"github.com/DimaSSV/diadocclient/pkg/credentials"
+	"github.com/DimaSSV/diadocclient/pkg/tokenstore"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+// command подкоманда; args - аргументы после имени команды
+type command struct {
+	usage string
+	run   func(ctx context.Context, app *app, args []string) error
+}
+
+var commands = map[string]command{
+	"orgs":            {"организации текущего пользователя", runOrgs},
+	"boxes":           {"ящики организаций текущего пользователя", runBoxes},
+	"docs list":       {"список документов ящика (GetDocuments)", runDocsList},
+	"doc get":         {"документ по идентификаторам сообщения и сущности", runDocGet},
+	"entity download": {"содержимое сущности сообщения", runEntityDownload},
+	"events tail":     {"непрерывный вывод новых событий ящика (с -output json - JSON Lines)", runEventsTail},
+	"counteragents":   {"контрагенты организации", runCounteragents},
+	"employees":       {"сотрудники ящика", runEmployees},
+	"shelf upload":    {"загрузка файла на полку", runShelfUpload},
+	"shelf download":  {"получение файла с полки", runShelfDownload},
+	"print-form":      {"печатная форма документа (PDF)", runPrintForm},
+}
+
+// app общее состояние команд
+type app struct {
+	client diadoc.DiadocClient
+	out    output
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "diadoc:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("diadoc", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	config := flags.String("config", "", "файл с учетными данными (YAML или JSON: login, password, clientId)")
+	format := flags.String("output", "table", "формат вывода: table, json или text (protobuf text)")
+	noTokenCache := flags.Bool("no-token-cache", false, "не сохранять токен между запусками")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Использование: diadoc [флаги] команда [флаги команды]")
+		fmt.Fprintln(stderr, "\nКоманды:")
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(stderr, "  %-16s %s\n", name, commands[name].usage)
+		}
+		fmt.Fprintln(stderr, "\nФлаги:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	out, err := newOutput(*format, stdout)
+	if err != nil {
+		return err
+	}
+	name, cmd, rest, ok := lookupCommand(flags.Args())
+	if !ok {
+		flags.Usage()
+		if len(flags.Args()) == 0 {
+			return flag.ErrHelp
+		}
+		return fmt.Errorf("неизвестная команда %q", strings.Join(flags.Args(), " "))
+	}
+	var provider credentials.Provider = credentials.Env{}
+	if *config != "" {
+		provider = credentials.File{Path: *config}
+	}
+	client := diadoc.NewWithCredentials(provider, "")
+	if !*noTokenCache {
+		if dir, err := os.UserCacheDir(); err == nil {
+			client.SetTokenStore(tokenstore.NewFile(filepath.Join(dir, "diadoc", "tokens.json")))
+		}
+	}
+	if err = cmd.run(ctx, &app{client: client, out: out, stdout: stdout, stderr: stderr}, rest); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// lookupCommand находит команду из одного или двух слов
+func lookupCommand(args []string) (string, command, []string, bool) {
+	if len(args) >= 2 {
+		name := args[0] + " " + args[1]
+		if cmd, ok := commands[name]; ok {
+			return name, cmd, args[2:], true
+		}
+	}
+	if len(args) >= 1 {
+		if cmd, ok := commands[args[0]]; ok {
+			return args[0], cmd, args[1:], true
+		}
+	}
+	return "", command{}, nil, false
+}
+
+// newFlagSet создает набор флагов команды, справка и ошибки разбора которого выводятся в stderr приложения
+func (a *app) newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet("diadoc "+name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	return flags
+}
+
+// required проверяет, что обязательные флаги заполнены
+func required(values map[string]string) error {
+	var missing []string
+	for name, value := range values {
+		if value == "" {
+			missing = append(missing, "-"+name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("не указаны обязательные флаги %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
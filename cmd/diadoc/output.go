@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+type outputFormat string
+
+const (
+	formatTable outputFormat = "table"
+	formatJSON  outputFormat = "json"
+	formatText  outputFormat = "text"
+)
+
+// output выводит результат команды: таблицей из выбранных полей или целиком
+// в JSON или текстовом формате protobuf
+type output struct {
+	format outputFormat
+	w      io.Writer
+}
+
+func newOutput(format string, w io.Writer) (output, error) {
+	switch outputFormat(format) {
+	case formatTable, formatJSON, formatText:
+		return output{format: outputFormat(format), w: w}, nil
+	}
+	return output{}, fmt.Errorf("неизвестный формат вывода %q", format)
+}
+
+// table строки таблицы для формата table
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func (t *table) add(values ...string) {
+	t.rows = append(t.rows, values)
+}
+
+// print выводит message в формате json/text или t в формате table
+func (o output) print(message proto.Message, t *table) error {
+	switch o.format {
+	case formatJSON:
+		data, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", AllowPartial: true}.Marshal(message)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(o.w, string(data))
+		return err
+	case formatText:
+		data, err := prototext.MarshalOptions{Multiline: true, Indent: "  ", AllowPartial: true}.Marshal(message)
+		if err != nil {
+			return err
+		}
+		_, err = o.w.Write(data)
+		return err
+	}
+	writer := tabwriter.NewWriter(o.w, 0, 4, 2, ' ', 0)
+	if len(t.header) > 0 {
+		fmt.Fprintln(writer, strings.Join(t.header, "\t"))
+	}
+	for _, row := range t.rows {
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+	return writer.Flush()
+}
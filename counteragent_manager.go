@@ -0,0 +1,156 @@
+package diadocсlient
+
+import (
+	"context"
+	"github.com/DimaSSV/diadocclient/pkg/async"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"google.golang.org/protobuf/proto"
+)
+
+// CounteragentManager управляет списком контрагентов организации: приглашения, ответы на входящие приглашения,
+// получение списка контрагентов с постраничной загрузкой
+type CounteragentManager struct {
+	client       DiadocClient
+	orgID        string
+	departmentID string
+	backoff      async.Backoff
+}
+
+// CounteragentResult результат массовой операции для одного контрагента
+type CounteragentResult struct {
+	OrgID string
+	// Result заполняется при принятии приглашения
+	Result *model.AcquireCounteragentResult
+	Err    error
+}
+
+// NewCounteragentManager создает менеджер для организации myOrgID. Если задан myDepartmentID,
+// приглашения отправляются от имени подразделения
+func (c DiadocClient) NewCounteragentManager(myOrgID string, myDepartmentID string) *CounteragentManager {
+	return &CounteragentManager{
+		client:       c,
+		orgID:        myOrgID,
+		departmentID: myDepartmentID,
+		backoff:      async.DefaultBackoff,
+	}
+}
+
+// Backoff задает паузы между опросами результата приглашения
+func (m *CounteragentManager) Backoff(backoff async.Backoff) *CounteragentManager {
+	m.backoff = backoff
+	return m
+}
+
+// Invite приглашает организацию counteragentOrgID к обмену документами и дожидается выполнения задачи.
+// invitation - необязательный документ, прикладываемый к приглашению
+func (m *CounteragentManager) Invite(ctx context.Context, counteragentOrgID string, message string, invitation *model.InvitationDocument) (*model.AcquireCounteragentResult, error) {
+	return m.acquire(ctx, &model.AcquireCounteragentRequest{
+		OrgId:                 proto.String(counteragentOrgID),
+		MessageToCounteragent: optionalString(message),
+		InvitationDocument:    invitation,
+	})
+}
+
+// InviteByInn приглашает организацию по ИНН
+func (m *CounteragentManager) InviteByInn(ctx context.Context, inn string, message string, invitation *model.InvitationDocument) (*model.AcquireCounteragentResult, error) {
+	return m.acquire(ctx, &model.AcquireCounteragentRequest{
+		Inn:                   proto.String(inn),
+		MessageToCounteragent: optionalString(message),
+		InvitationDocument:    invitation,
+	})
+}
+
+// Accept принимает приглашение организации counteragentOrgID
+func (m *CounteragentManager) Accept(ctx context.Context, counteragentOrgID string, message string) (*model.AcquireCounteragentResult, error) {
+	return m.Invite(ctx, counteragentOrgID, message, nil)
+}
+
+// Reject отклоняет приглашение организации counteragentOrgID (или разрывает отношения с контрагентом)
+func (m *CounteragentManager) Reject(ctx context.Context, counteragentOrgID string, comment string) error {
+	return m.client.BreakWithCounteragent(ctx, m.orgID, counteragentOrgID, comment)
+}
+
+func (m *CounteragentManager) acquire(ctx context.Context, request *model.AcquireCounteragentRequest) (*model.AcquireCounteragentResult, error) {
+	task, err := m.client.AcquireCounteragent(ctx, m.orgID, m.departmentID, request)
+	if err != nil {
+		return nil, err
+	}
+	return m.client.WaitAcquireCounteragentResult(ctx, task.GetTaskId(), m.backoff)
+}
+
+// List возвращает всех контрагентов в статусе status, загружая страницы GetCounteragentsV2 до конца списка.
+// Для UnknownCounteragentStatus возвращаются контрагенты во всех статусах
+func (m *CounteragentManager) List(ctx context.Context, status model.CounteragentStatus) ([]*model.Counteragent, error) {
+	counteragentStatus := ""
+	if status != model.CounteragentStatus_UnknownCounteragentStatus {
+		counteragentStatus = status.String()
+	}
+	var result []*model.Counteragent
+	afterIndexKey := ""
+	for {
+		list, err := m.client.GetCounteragentsV2(ctx, m.orgID, counteragentStatus, afterIndexKey)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, list.Counteragents...)
+		if len(list.Counteragents) == 0 ||
+			list.GetTotalCountType() == model.TotalCountType_Equal && len(result) >= int(list.GetTotalCount()) {
+			return result, nil
+		}
+		afterIndexKey = list.Counteragents[len(list.Counteragents)-1].GetIndexKey()
+	}
+}
+
+// Pending возвращает организации, приглашения которых ожидают ответа
+func (m *CounteragentManager) Pending(ctx context.Context) ([]*model.Counteragent, error) {
+	return m.List(ctx, model.CounteragentStatus_InvitesMe)
+}
+
+// AcceptPending принимает входящие приглашения, для которых accept возвращает true (nil - все).
+// Ошибка по одному контрагенту не прерывает обработку остальных и возвращается в CounteragentResult.Err
+func (m *CounteragentManager) AcceptPending(ctx context.Context, message string, accept func(*model.Counteragent) bool) ([]CounteragentResult, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var results []CounteragentResult
+	for _, counteragent := range pending {
+		if accept != nil && !accept(counteragent) {
+			continue
+		}
+		orgID := counteragent.GetOrganization().GetOrgId()
+		result, err := m.Accept(ctx, orgID, message)
+		results = append(results, CounteragentResult{OrgID: orgID, Result: result, Err: err})
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+	}
+	return results, nil
+}
+
+// RejectPending отклоняет входящие приглашения, для которых reject возвращает true (nil - все)
+func (m *CounteragentManager) RejectPending(ctx context.Context, comment string, reject func(*model.Counteragent) bool) ([]CounteragentResult, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var results []CounteragentResult
+	for _, counteragent := range pending {
+		if reject != nil && !reject(counteragent) {
+			continue
+		}
+		orgID := counteragent.GetOrganization().GetOrgId()
+		results = append(results, CounteragentResult{OrgID: orgID, Err: m.Reject(ctx, orgID, comment)})
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+	}
+	return results, nil
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return proto.String(value)
+}
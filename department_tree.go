@@ -0,0 +1,547 @@
+package diadocсlient
+
+import (
+	"context"
+	"fmt"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// DepartmentNode узел дерева подразделений. У корневого узла (головного подразделения) Department = nil
+type DepartmentNode struct {
+	Department *model.DepartmentAdmin
+	Parent     *DepartmentNode
+	Children   []*DepartmentNode
+}
+
+func (n *DepartmentNode) ID() string {
+	if n.Department == nil {
+		return headDepartmentID
+	}
+	return n.Department.GetId()
+}
+
+func (n *DepartmentNode) Name() string {
+	return n.Department.GetName()
+}
+
+// IsRoot возвращает true для головного подразделения
+func (n *DepartmentNode) IsRoot() bool {
+	return n.Department == nil
+}
+
+// Path возвращает имена подразделений от верхнего уровня до текущего (без головного подразделения)
+func (n *DepartmentNode) Path() []string {
+	var path []string
+	for node := n; node != nil && !node.IsRoot(); node = node.Parent {
+		path = append([]string{node.Name()}, path...)
+	}
+	return path
+}
+
+// DepartmentTree дерево подразделений ящика
+type DepartmentTree struct {
+	BoxID string
+	Root  *DepartmentNode
+	byID  map[string]*DepartmentNode
+}
+
+// NewDepartmentTree строит дерево из плоского списка подразделений. Подразделения без родителя,
+// с неизвестным родителем или входящие в цикл родителей считаются подразделениями верхнего уровня
+func NewDepartmentTree(boxID string, departments []*model.DepartmentAdmin) *DepartmentTree {
+	t := &DepartmentTree{
+		BoxID: boxID,
+		Root:  &DepartmentNode{},
+		byID:  make(map[string]*DepartmentNode, len(departments)+1),
+	}
+	t.byID[headDepartmentID] = t.Root
+	for _, department := range departments {
+		t.byID[department.GetId()] = &DepartmentNode{Department: department}
+	}
+	for _, department := range departments {
+		node := t.byID[department.GetId()]
+		parent, ok := t.byID[department.GetParentDepartmentId()]
+		if !ok || inParentCycle(t.byID, node) {
+			parent = t.Root
+		}
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+	return t
+}
+
+// inParentCycle сообщает, что цепочка родителей подразделения node возвращается к нему самому
+func inParentCycle(byID map[string]*DepartmentNode, node *DepartmentNode) bool {
+	visited := make(map[*DepartmentNode]bool)
+	for current := node; current.Department != nil && !visited[current]; {
+		visited[current] = true
+		parent, ok := byID[current.Department.GetParentDepartmentId()]
+		if !ok {
+			return false
+		}
+		if parent == node {
+			return true
+		}
+		current = parent
+	}
+	return false
+}
+
+// GetDepartmentTree загружает все страницы GetDepartmentsFull и строит дерево подразделений ящика
+func (c DiadocClient) GetDepartmentTree(ctx context.Context, boxID string) (*DepartmentTree, error) {
+	departments, err := c.ListAllDepartments(ctx, boxID)
+	if err != nil {
+		return nil, err
+	}
+	return NewDepartmentTree(boxID, departments), nil
+}
+
+// Find возвращает подразделение по идентификатору
+func (t *DepartmentTree) Find(departmentID string) *DepartmentNode {
+	return t.byID[departmentID]
+}
+
+// FindByName возвращает подразделения с указанным наименованием (без учета регистра)
+func (t *DepartmentTree) FindByName(name string) []*DepartmentNode {
+	return t.Filter(func(node *DepartmentNode) bool {
+		return strings.EqualFold(node.Name(), name)
+	})
+}
+
+// FindByKpp возвращает подразделения с указанным КПП
+func (t *DepartmentTree) FindByKpp(kpp string) []*DepartmentNode {
+	return t.Filter(func(node *DepartmentNode) bool {
+		return node.Department.GetKpp() == kpp
+	})
+}
+
+// FindByPath возвращает подразделение по цепочке наименований от верхнего уровня
+func (t *DepartmentTree) FindByPath(path ...string) *DepartmentNode {
+	node := t.Root
+	for _, name := range path {
+		node = childByName(node, name)
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// Filter возвращает подразделения (кроме головного), удовлетворяющие условию, в порядке обхода дерева
+func (t *DepartmentTree) Filter(match func(node *DepartmentNode) bool) []*DepartmentNode {
+	var result []*DepartmentNode
+	t.Walk(func(node *DepartmentNode, depth int) bool {
+		if match(node) {
+			result = append(result, node)
+		}
+		return true
+	})
+	return result
+}
+
+// Walk обходит подразделения (кроме головного) в глубину, родитель раньше потомков.
+// Если fn возвращает false, потомки узла не обходятся
+func (t *DepartmentTree) Walk(fn func(node *DepartmentNode, depth int) bool) {
+	var walk func(node *DepartmentNode, depth int)
+	walk = func(node *DepartmentNode, depth int) {
+		for _, child := range node.Children {
+			if fn(child, depth) {
+				walk(child, depth+1)
+			}
+		}
+	}
+	walk(t.Root, 0)
+}
+
+func formatDepartmentPath(path []string) string {
+	if len(path) == 0 {
+		return "(головное подразделение)"
+	}
+	return strings.Join(path, " / ")
+}
+
+func childByName(node *DepartmentNode, name string) *DepartmentNode {
+	for _, child := range node.Children {
+		if strings.EqualFold(child.Name(), name) {
+			return child
+		}
+	}
+	return nil
+}
+
+// DepartmentSpec желаемое состояние подразделения. Существующее подразделение определяется по ID,
+// а если он не задан - по наименованию: сначала среди потомков родителя, затем во всем дереве
+// (в этом случае подразделение переносится). Kpp и Routing, если не заданы, не изменяются
+type DepartmentSpec struct {
+	ID           string                 `json:"id,omitempty" yaml:"id,omitempty"`
+	Name         string                 `json:"name" yaml:"name"`
+	Abbreviation string                 `json:"abbreviation,omitempty" yaml:"abbreviation,omitempty"`
+	Kpp          *string                `json:"kpp,omitempty" yaml:"kpp,omitempty"`
+	Routing      *DepartmentRoutingSpec `json:"routing,omitempty" yaml:"routing,omitempty"`
+	Children     []DepartmentSpec       `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+type DepartmentRoutingSpec struct {
+	Kpp     bool `json:"kpp" yaml:"kpp"`
+	Address bool `json:"address" yaml:"address"`
+}
+
+// ParseDepartmentSpec разбирает список подразделений верхнего уровня в формате YAML или JSON
+func ParseDepartmentSpec(data []byte) ([]DepartmentSpec, error) {
+	var spec []DepartmentSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	var check func(specs []DepartmentSpec) error
+	check = func(specs []DepartmentSpec) error {
+		for _, department := range specs {
+			if department.Name == "" {
+				return fmt.Errorf("в спецификации не указано наименование подразделения")
+			}
+			if err := check(department.Children); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := check(spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+type DepartmentOperationKind string
+
+const (
+	DepartmentCreate DepartmentOperationKind = "create"
+	DepartmentUpdate DepartmentOperationKind = "update"
+	DepartmentDelete DepartmentOperationKind = "delete"
+)
+
+// DepartmentOperation одно изменение плана
+type DepartmentOperation struct {
+	Kind DepartmentOperationKind
+	// DepartmentID пусто для создаваемых подразделений
+	DepartmentID string
+	Path         []string
+	Changes      []string
+
+	ref    *departmentRef
+	parent *departmentRef
+	create *model.DepartmentToCreate
+	update *model.DepartmentToUpdate
+}
+
+// DepartmentPlan список изменений в порядке выполнения: создания и переносы от верхних уровней
+// к нижним, затем удаления от потомков к родителям
+type DepartmentPlan struct {
+	BoxID      string
+	Operations []DepartmentOperation
+}
+
+// departmentRef ссылка на подразделение, идентификатор которого для создаваемых
+// подразделений становится известен только при выполнении плана
+type departmentRef struct {
+	id     string
+	name   string
+	parent *departmentRef
+}
+
+// String выводит план в читаемом виде (для пробного запуска)
+func (p *DepartmentPlan) String() string {
+	if len(p.Operations) == 0 {
+		return "Изменений нет\n"
+	}
+	var buf strings.Builder
+	for _, op := range p.Operations {
+		fmt.Fprintf(&buf, "%s %s\n", op.Kind, formatDepartmentPath(op.Path))
+		for _, change := range op.Changes {
+			fmt.Fprintf(&buf, "    %s\n", change)
+		}
+	}
+	return buf.String()
+}
+
+// Diff сравнивает дерево с желаемым списком подразделений верхнего уровня и возвращает план изменений.
+// При deleteUnlisted подразделения, отсутствующие в спецификации, удаляются
+func (t *DepartmentTree) Diff(desired []DepartmentSpec, deleteUnlisted bool) (*DepartmentPlan, error) {
+	d := &departmentDiff{
+		tree:    t,
+		plan:    &DepartmentPlan{BoxID: t.BoxID},
+		matched: make(map[string]bool),
+		planned: make(map[string]*departmentRef),
+	}
+	if err := d.matchIDs(desired); err != nil {
+		return nil, err
+	}
+	root := &departmentRef{id: headDepartmentID}
+	if err := d.diffChildren(t.Root, root, nil, desired); err != nil {
+		return nil, err
+	}
+	if deleteUnlisted {
+		d.deleteUnmatched(t.Root)
+	}
+	return d.plan, nil
+}
+
+type departmentDiff struct {
+	tree    *DepartmentTree
+	plan    *DepartmentPlan
+	matched map[string]bool
+	// planned новый родитель подразделений, перенос которых уже добавлен в план
+	planned map[string]*departmentRef
+}
+
+// matchIDs заранее резервирует подразделения, указанные по ID, чтобы они не были сопоставлены по наименованию
+func (d *departmentDiff) matchIDs(specs []DepartmentSpec) error {
+	for _, spec := range specs {
+		if spec.ID != "" {
+			if d.tree.Find(spec.ID) == nil || spec.ID == headDepartmentID {
+				return fmt.Errorf("подразделение %s (%s) не найдено", spec.ID, spec.Name)
+			}
+			if d.matched[spec.ID] {
+				return fmt.Errorf("подразделение %s указано в спецификации несколько раз", spec.ID)
+			}
+			d.matched[spec.ID] = true
+		}
+		if err := d.matchIDs(spec.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffChildren сравнивает потомков узла current (nil для создаваемого родителя) со спецификацией
+func (d *departmentDiff) diffChildren(current *DepartmentNode, parent *departmentRef, path []string, specs []DepartmentSpec) error {
+	for _, spec := range specs {
+		childPath := append(append([]string{}, path...), spec.Name)
+		node := d.match(current, parent, spec)
+		if node != nil && d.isPlannedAncestorOrSelf(node, parent) {
+			return fmt.Errorf("подразделение %s нельзя перенести в подчиненное ему подразделение %s",
+				formatDepartmentPath(node.Path()), formatDepartmentPath(path))
+		}
+		if node == nil {
+			ref := &departmentRef{name: spec.Name, parent: parent}
+			d.plan.Operations = append(d.plan.Operations, createDepartmentOperation(ref, childPath, spec))
+			if err := d.diffChildren(nil, ref, childPath, spec.Children); err != nil {
+				return err
+			}
+			continue
+		}
+		ref := &departmentRef{id: node.ID(), name: node.Name(), parent: parent}
+		if op, ok := updateDepartmentOperation(node, ref, parent, childPath, spec); ok {
+			d.plan.Operations = append(d.plan.Operations, op)
+			if op.update.ParentDepartment != nil {
+				d.planned[node.ID()] = parent
+			}
+		}
+		if err := d.diffChildren(node, ref, childPath, spec.Children); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// match находит существующее подразделение для spec под родителем parent (current - его узел в дереве).
+// По наименованию во всем дереве не выбираются подразделения, в подчинение которым к моменту переноса
+// будет входить parent: перенос в собственное подчиненное подразделение невозможен, такое подразделение будет создано
+func (d *departmentDiff) match(current *DepartmentNode, parent *departmentRef, spec DepartmentSpec) *DepartmentNode {
+	if spec.ID != "" {
+		return d.tree.Find(spec.ID)
+	}
+	if current != nil {
+		for _, child := range current.Children {
+			if !d.matched[child.ID()] && strings.EqualFold(child.Name(), spec.Name) {
+				d.matched[child.ID()] = true
+				return child
+			}
+		}
+	}
+	var candidates []*DepartmentNode
+	for _, node := range d.tree.FindByName(spec.Name) {
+		if !d.matched[node.ID()] && !d.isPlannedAncestorOrSelf(node, parent) {
+			candidates = append(candidates, node)
+		}
+	}
+	// Переносим только однозначно определяемое подразделение
+	if len(candidates) != 1 {
+		return nil
+	}
+	d.matched[candidates[0].ID()] = true
+	return candidates[0]
+}
+
+// isPlannedAncestorOrSelf сообщает, что node совпадает с подразделением ref или будет его предком к моменту
+// выполнения очередной операции плана: для уже запланированных переносов берется новый родитель, для остальных
+// подразделений - текущий, для создаваемых - родитель из спецификации
+func (d *departmentDiff) isPlannedAncestorOrSelf(node *DepartmentNode, ref *departmentRef) bool {
+	for ref != nil {
+		if ref.id == "" {
+			ref = ref.parent
+			continue
+		}
+		if ref.id == node.ID() {
+			return true
+		}
+		if parent, ok := d.planned[ref.id]; ok {
+			ref = parent
+			continue
+		}
+		current := d.tree.Find(ref.id)
+		if current == nil || current.Parent == nil {
+			return false
+		}
+		ref = &departmentRef{id: current.Parent.ID()}
+	}
+	return false
+}
+
+// deleteUnmatched добавляет удаление несопоставленных подразделений, потомков раньше родителей.
+// Сопоставленные потомки к этому моменту уже перенесены предыдущими операциями плана
+func (d *departmentDiff) deleteUnmatched(node *DepartmentNode) {
+	for _, child := range node.Children {
+		d.deleteUnmatched(child)
+		if !d.matched[child.ID()] {
+			d.plan.Operations = append(d.plan.Operations, DepartmentOperation{
+				Kind:         DepartmentDelete,
+				DepartmentID: child.ID(),
+				Path:         child.Path(),
+			})
+		}
+	}
+}
+
+func createDepartmentOperation(ref *departmentRef, path []string, spec DepartmentSpec) DepartmentOperation {
+	abbreviation := spec.Abbreviation
+	if abbreviation == "" {
+		abbreviation = spec.Name
+	}
+	routing := &model.Routing{Kpp: proto.Bool(false), Address: proto.Bool(false)}
+	if spec.Routing != nil {
+		routing = &model.Routing{Kpp: proto.Bool(spec.Routing.Kpp), Address: proto.Bool(spec.Routing.Address)}
+	}
+	create := &model.DepartmentToCreate{
+		Name:         proto.String(spec.Name),
+		Abbreviation: proto.String(abbreviation),
+		Routing:      routing,
+	}
+	changes := []string{fmt.Sprintf("abbreviation: %s", abbreviation)}
+	if spec.Kpp != nil && *spec.Kpp != "" {
+		create.Kpp = proto.String(*spec.Kpp)
+		changes = append(changes, fmt.Sprintf("kpp: %s", *spec.Kpp))
+	}
+	return DepartmentOperation{
+		Kind:    DepartmentCreate,
+		Path:    path,
+		Changes: changes,
+		ref:     ref,
+		parent:  ref.parent,
+		create:  create,
+	}
+}
+
+func updateDepartmentOperation(node *DepartmentNode, ref *departmentRef, parent *departmentRef, path []string, spec DepartmentSpec) (DepartmentOperation, bool) {
+	department := node.Department
+	update := &model.DepartmentToUpdate{}
+	var changes []string
+	if parent.id == "" || parent.id != node.Parent.ID() {
+		// Для нового родителя идентификатор подставляется при выполнении плана
+		update.ParentDepartment = &model.ParentDepartmentPatch{}
+		changes = append(changes, fmt.Sprintf("parent: %s -> %s", formatDepartmentPath(node.Parent.Path()), formatDepartmentPath(path[:len(path)-1])))
+	}
+	abbreviation := department.GetAbbreviation()
+	if spec.Abbreviation != "" {
+		abbreviation = spec.Abbreviation
+	}
+	if spec.Name != department.GetName() || abbreviation != department.GetAbbreviation() {
+		update.DepartmentNaming = &model.DepartmentNamingPatch{Name: proto.String(spec.Name), Abbreviation: proto.String(abbreviation)}
+		changes = append(changes, fmt.Sprintf("name: %q (%q) -> %q (%q)", department.GetName(), department.GetAbbreviation(), spec.Name, abbreviation))
+	}
+	if spec.Kpp != nil && *spec.Kpp != department.GetKpp() {
+		update.Kpp = &model.DepartmentKppPatch{Kpp: optionalString(*spec.Kpp)}
+		changes = append(changes, fmt.Sprintf("kpp: %q -> %q", department.GetKpp(), *spec.Kpp))
+	}
+	routing := department.GetRouting()
+	if spec.Routing != nil && (spec.Routing.Kpp != routing.GetKpp() || spec.Routing.Address != routing.GetAddress()) {
+		update.Routing = &model.DepartmentRoutingPatch{Kpp: proto.Bool(spec.Routing.Kpp), Address: proto.Bool(spec.Routing.Address)}
+		changes = append(changes, fmt.Sprintf("routing: kpp=%t address=%t -> kpp=%t address=%t", routing.GetKpp(), routing.GetAddress(), spec.Routing.Kpp, spec.Routing.Address))
+	}
+	if len(changes) == 0 {
+		return DepartmentOperation{}, false
+	}
+	return DepartmentOperation{
+		Kind:         DepartmentUpdate,
+		DepartmentID: node.ID(),
+		Path:         path,
+		Changes:      changes,
+		ref:          ref,
+		parent:       parent,
+		update:       update,
+	}, true
+}
+
+// ApplyDepartmentPlan выполняет план. Выполнение останавливается на первой ошибке;
+// возвращается количество выполненных операций
+func (c DiadocClient) ApplyDepartmentPlan(ctx context.Context, plan *DepartmentPlan) (int, error) {
+	resolver := &departmentResolver{client: c, boxID: plan.BoxID}
+	for i, op := range plan.Operations {
+		var err error
+		switch op.Kind {
+		case DepartmentCreate:
+			var parentID string
+			if parentID, err = resolver.resolve(ctx, op.parent); err == nil {
+				op.create.ParentDepartmentId = proto.String(parentID)
+				err = c.CreateDepartment(ctx, plan.BoxID, op.create)
+			}
+		case DepartmentUpdate:
+			if op.update.ParentDepartment != nil {
+				var parentID string
+				if parentID, err = resolver.resolve(ctx, op.parent); err != nil {
+					break
+				}
+				op.update.ParentDepartment.ParentDepartmentId = proto.String(parentID)
+			}
+			err = c.UpdateDepartment(ctx, plan.BoxID, op.DepartmentID, op.update)
+		case DepartmentDelete:
+			err = c.DeleteDepartment(ctx, plan.BoxID, op.DepartmentID)
+		}
+		if err != nil {
+			return i, fmt.Errorf("%s %s: %w", op.Kind, formatDepartmentPath(op.Path), err)
+		}
+	}
+	return len(plan.Operations), nil
+}
+
+// departmentResolver определяет идентификаторы созданных подразделений. CreateDepartment не возвращает
+// созданное подразделение, поэтому оно ищется по наименованию среди потомков родителя в заново загруженном дереве
+type departmentResolver struct {
+	client DiadocClient
+	boxID  string
+	tree   *DepartmentTree
+}
+
+func (r *departmentResolver) resolve(ctx context.Context, ref *departmentRef) (string, error) {
+	if ref.id != "" {
+		return ref.id, nil
+	}
+	parentID, err := r.resolve(ctx, ref.parent)
+	if err != nil {
+		return "", err
+	}
+	for attempt := 0; attempt < 2; attempt++ {
+		if r.tree == nil || attempt > 0 {
+			if r.tree, err = r.client.GetDepartmentTree(ctx, r.boxID); err != nil {
+				return "", err
+			}
+		}
+		if parent := r.tree.Find(parentID); parent != nil {
+			if node := childByName(parent, ref.name); node != nil {
+				ref.id = node.ID()
+				return ref.id, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("не найдено созданное подразделение %s", ref.name)
+}
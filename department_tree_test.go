@@ -0,0 +1,68 @@
+package diadocсlient
+
+import (
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+func testDepartment(id string, parentID string, name string) *model.DepartmentAdmin {
+	return &model.DepartmentAdmin{
+		Id:                 proto.String(id),
+		ParentDepartmentId: proto.String(parentID),
+		Name:               proto.String(name),
+		Abbreviation:       proto.String(name),
+	}
+}
+
+func TestDepartmentTreeDiffSwap(t *testing.T) {
+	// A -> B переставляется в B -> A: оба подразделения переносятся, а не создаются заново
+	tests := []struct {
+		name string
+		spec []DepartmentSpec
+	}{
+		{"by name", []DepartmentSpec{{Name: "B", Children: []DepartmentSpec{{Name: "A"}}}}},
+		{"by id", []DepartmentSpec{{ID: "b", Name: "B", Children: []DepartmentSpec{{ID: "a", Name: "A"}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := NewDepartmentTree("box", []*model.DepartmentAdmin{
+				testDepartment("a", headDepartmentID, "A"),
+				testDepartment("b", "a", "B"),
+			})
+			plan, err := tree.Diff(tt.spec, true)
+			if err != nil {
+				t.Fatalf("Diff: %v", err)
+			}
+			if len(plan.Operations) != 2 {
+				t.Fatalf("got %d operations, want 2:\n%s", len(plan.Operations), plan)
+			}
+			want := []struct {
+				id     string
+				parent string
+			}{{"b", headDepartmentID}, {"a", "b"}}
+			for i, op := range plan.Operations {
+				if op.Kind != DepartmentUpdate || op.DepartmentID != want[i].id {
+					t.Fatalf("operation %d = %s %s, want update %s:\n%s", i, op.Kind, op.DepartmentID, want[i].id, plan)
+				}
+				if op.update.ParentDepartment == nil || op.parent.id != want[i].parent {
+					t.Errorf("operation %d moves %s under %q, want %q", i, op.DepartmentID, op.parent.id, want[i].parent)
+				}
+			}
+		})
+	}
+}
+
+func TestDepartmentTreeCycle(t *testing.T) {
+	tree := NewDepartmentTree("box", []*model.DepartmentAdmin{
+		testDepartment("a", "b", "A"),
+		testDepartment("b", "a", "B"),
+		testDepartment("c", "a", "C"),
+	})
+	if len(tree.Root.Children) != 2 {
+		t.Fatalf("got %d top-level departments, want 2", len(tree.Root.Children))
+	}
+	if parent := tree.Find("c").Parent; parent != tree.Find("a") {
+		t.Errorf("C parent = %s, want A", parent.Name())
+	}
+}
@@ -11,26 +11,71 @@ import (
 	"github.com/DimaSSV/diadocclient/internal/service/event"
 	"github.com/DimaSSV/diadocclient/internal/service/message"
 	"github.com/DimaSSV/diadocclient/internal/service/organization"
+	"github.com/DimaSSV/diadocclient/internal/service/reply"
+	"github.com/DimaSSV/diadocclient/internal/service/signing"
 	"github.com/DimaSSV/diadocclient/internal/service/template"
+	"github.com/DimaSSV/diadocclient/pkg/async"
+	"github.com/DimaSSV/diadocclient/pkg/credentials"
+	"github.com/DimaSSV/diadocclient/pkg/filter"
 	"github.com/DimaSSV/diadocclient/pkg/model"
+	"github.com/DimaSSV/diadocclient/pkg/ratelimit"
+	"github.com/DimaSSV/diadocclient/pkg/signer"
+	"github.com/DimaSSV/diadocclient/pkg/tokenstore"
+	"net/http"
+	"time"
 )
 
 type DiadocClient struct {
 	adapter *adapter.Adapter
 }
 
+// New создает клиента. Вход в Диадок выполняется при первом запросе (если не передан initialToken
+// и токен не найден в хранилище, заданном SetTokenStore), поэтому ошибка авторизации возвращается первым вызовом API
 func New(login string, password string, clientID string, initialToken string) (DiadocClient, error) {
 	client := DiadocClient{
 		adapter: adapter.New(login, password, clientID, initialToken),
 	}
-	if len(initialToken) == 0 {
-		if err := client.adapter.UpdateToken(context.Background()); err != nil {
-			return client, err
-		}
-	}
 	return client, nil
 }
 
+// NewWithCredentials создает клиента, запрашивающего учетные данные у provider при каждом входе
+// (см. пакет pkg/credentials: переменные окружения, файл, функция)
+func NewWithCredentials(provider credentials.Provider, initialToken string) DiadocClient {
+	return DiadocClient{
+		adapter: adapter.NewWithCredentials(provider, initialToken),
+	}
+}
+
+// SetTokenStore задает хранилище токенов, чтобы токен переживал перезапуск процесса
+func (c DiadocClient) SetTokenStore(store tokenstore.Store) {
+	c.adapter.SetTokenStore(store)
+}
+
+// SetTokenMaxAge задает возраст токена, после которого он обновляется заранее, до получения ответа 401
+func (c DiadocClient) SetTokenMaxAge(maxAge time.Duration) {
+	c.adapter.SetTokenMaxAge(maxAge)
+}
+
+// TokenAge возвращает возраст текущего токена; false, если вход еще не выполнялся
+func (c DiadocClient) TokenAge() (time.Duration, bool) {
+	return c.adapter.TokenAge()
+}
+
+// SetRateLimiter ограничивает частоту запросов клиента. Один limiter можно передать нескольким клиентам
+func (c DiadocClient) SetRateLimiter(limiter *ratelimit.Limiter) {
+	c.adapter.SetRateLimiter(limiter)
+}
+
+// SetTransport задает транспорт HTTP-запросов клиента (nil - http.DefaultTransport)
+func (c DiadocClient) SetTransport(transport http.RoundTripper) {
+	c.adapter.SetTransport(transport)
+}
+
+// RefreshToken заново получает авторизационный токен
+func (c DiadocClient) RefreshToken(ctx context.Context) error {
+	return c.adapter.UpdateToken(ctx)
+}
+
 /////////////////////////////////////////////////////////////////
 ////////////////Работа с организациями///////////////////////////
 /////////////////////////////////////////////////////////////////
@@ -164,7 +209,11 @@ func (c DiadocClient) AcquireCounteragent(ctx context.Context, myOrgID string, m
 }
 
 func (c DiadocClient) AcquireCounteragentResult(ctx context.Context, taskID string) (*model.AcquireCounteragentResult, error) {
-	return counteragent.AcquireCounteragentResult(ctx, c.adapter, taskID)
+	return counteragent.AcquireCounteragentResult(ctx, c.adapter, taskID, async.DefaultBackoff)
+}
+
+func (c DiadocClient) WaitAcquireCounteragentResult(ctx context.Context, taskID string, backoff async.Backoff) (*model.AcquireCounteragentResult, error) {
+	return counteragent.AcquireCounteragentResult(ctx, c.adapter, taskID, backoff)
 }
 
 func (c DiadocClient) BreakWithCounteragent(ctx context.Context, myOrgID string, counteragentOrgID string, comment string) error {
@@ -211,6 +260,14 @@ func (c DiadocClient) PostMessagePatch(ctx context.Context, operationID string,
 	return message.PostMessagePatch(ctx, c.adapter, operationID, post)
 }
 
+func (c DiadocClient) SignAndPostMessage(ctx context.Context, operationID string, post *model.MessageToPost, s signer.Signer) (*model.Message, error) {
+	return signing.PostMessage(ctx, c.adapter, operationID, post, s)
+}
+
+func (c DiadocClient) SignAndPostMessagePatch(ctx context.Context, operationID string, post *model.MessagePatchToPost, s signer.Signer) (*model.MessagePatch, error) {
+	return signing.PostMessagePatch(ctx, c.adapter, operationID, post, s)
+}
+
 ///////////////////////////////////////////////////////////////////
 /////////////////////Работа с событиями////////////////////////////
 ///////////////////////////////////////////////////////////////////
@@ -219,35 +276,8 @@ func (c DiadocClient) GetEvent(ctx context.Context, boxID string, eventID string
 	return event.GetEvent(ctx, c.adapter, boxID, eventID)
 }
 
-func (c DiadocClient) GetNewEvents(
-	ctx context.Context,
-	boxID string,
-	afterIndexKey string,
-	departmentID string,
-	messageTypes []string,
-	typeNamedIDs []string,
-	documentDirections []string,
-	timestampFromTicks int64,
-	timestampToTicks int64,
-	counteragentBoxID string,
-	orderBy string,
-	limit int,
-) (*model.BoxEventList, error) {
-	return event.GetNewEvents(
-		ctx,
-		c.adapter,
-		boxID,
-		afterIndexKey,
-		departmentID,
-		messageTypes,
-		typeNamedIDs,
-		documentDirections,
-		timestampFromTicks,
-		timestampToTicks,
-		counteragentBoxID,
-		orderBy,
-		limit,
-	)
+func (c DiadocClient) GetNewEvents(ctx context.Context, boxID string, options filter.Events) (*model.BoxEventList, error) {
+	return event.GetNewEvents(ctx, c.adapter, boxID, options)
 }
 
 func (c DiadocClient) GetLastEvent(ctx context.Context, boxID string) (*model.BoxEvent, error) {
@@ -318,6 +348,11 @@ func (c DiadocClient) GetForwardedDocuments(ctx context.Context, boxID string, r
 	return document.GetForwardedDocuments(ctx, c.adapter, boxID, request)
 }
 
+// GeneratePrintForm формирует печатную форму документа, ожидая ее готовности
+func (c DiadocClient) GeneratePrintForm(ctx context.Context, boxID string, messageID string, documentID string) (*document.GeneratedFile, error) {
+	return document.GeneratePrintForm(ctx, c.adapter, boxID, messageID, documentID, async.DefaultBackoff)
+}
+
 func (c DiadocClient) GetGeneratedPrintForm(ctx context.Context, printFormID string) ([]byte, error) {
 	return document.GetGeneratedPrintForm(ctx, c.adapter, printFormID)
 }
@@ -326,6 +361,10 @@ func (c DiadocClient) MoveDocuments(ctx context.Context, operation *model.Docume
 	return document.MoveDocuments(ctx, c.adapter, operation)
 }
 
+func (c DiadocClient) PrepareDocumentsToSign(ctx context.Context, request *model.PrepareDocumentsToSignRequest) (*model.PrepareDocumentsToSignResponse, error) {
+	return document.PrepareDocumentsToSign(ctx, c.adapter, request)
+}
+
 func (c DiadocClient) RecycleDraft(ctx context.Context, boxID string, draftID string) error {
 	return document.RecycleDraft(ctx, c.adapter, boxID, draftID)
 }
@@ -346,6 +385,10 @@ func (c DiadocClient) SendDraft(ctx context.Context, operationID string, send *m
 	return document.SendDraft(ctx, c.adapter, operationID, send)
 }
 
+func (c DiadocClient) SignAndSendDraft(ctx context.Context, operationID string, send *model.DraftToSend, s signer.Signer) (*model.Message, error) {
+	return signing.SendDraft(ctx, c.adapter, operationID, send, s)
+}
+
 ///////////////////////////////////////////////////////////////////
 //////////////////////Работа с шаблонами///////////////////////////
 ///////////////////////////////////////////////////////////////////
@@ -362,6 +405,78 @@ func (c DiadocClient) PostTemplatePatch(ctx context.Context, boxID string, templ
 	return template.PostTemplatePatch(ctx, c.adapter, boxID, templateID, operationID, post)
 }
 
+func (c DiadocClient) SignAndPostTemplatePatch(ctx context.Context, boxID string, templateID string, operationID string, post *model.TemplatePatchToPost, s signer.Signer) (*model.MessagePatch, error) {
+	return signing.PostTemplatePatch(ctx, c.adapter, boxID, templateID, operationID, post, s)
+}
+
 func (c DiadocClient) TransformTemplateToMessage(ctx context.Context, operationID string, post *model.TemplateTransformationToPost) (*model.Message, error) {
 	return template.TransformTemplateToMessage(ctx, c.adapter, operationID, post)
 }
+
+///////////////////////////////////////////////////////////////////
+/////////////Генерация и разбор служебных документов///////////////
+///////////////////////////////////////////////////////////////////
+
+func (c DiadocClient) GenerateReceiptXml(ctx context.Context, boxID string, messageID string, attachmentID string, signer *model.Signer) (*document.GeneratedFile, error) {
+	return document.GenerateReceiptXml(ctx, c.adapter, boxID, messageID, attachmentID, signer)
+}
+
+func (c DiadocClient) GenerateSignatureRejectionXml(ctx context.Context, boxID string, messageID string, attachmentID string, info *model.SignatureRejectionInfo) (*document.GeneratedFile, error) {
+	return document.GenerateSignatureRejectionXml(ctx, c.adapter, boxID, messageID, attachmentID, info)
+}
+
+func (c DiadocClient) GenerateRevocationRequestXml(ctx context.Context, boxID string, messageID string, attachmentID string, info *model.RevocationRequestInfo) (*document.GeneratedFile, error) {
+	return document.GenerateRevocationRequestXml(ctx, c.adapter, boxID, messageID, attachmentID, info)
+}
+
+func (c DiadocClient) GenerateRecipientTitleXml(ctx context.Context, boxID string, senderTitleMessageID string, senderTitleAttachmentID string, documentVersion string, userContractData []byte) (*document.GeneratedFile, error) {
+	return document.GenerateRecipientTitleXml(ctx, c.adapter, boxID, senderTitleMessageID, senderTitleAttachmentID, documentVersion, userContractData)
+}
+
+func (c DiadocClient) GenerateCorrectionRequestXml(ctx context.Context, boxID string, messageID string, attachmentID string, info *model.InvoiceCorrectionRequestInfo) (*document.GeneratedFile, error) {
+	return document.GenerateCorrectionRequestXml(ctx, c.adapter, boxID, messageID, attachmentID, info)
+}
+
+func (c DiadocClient) ParseRevocationRequestXml(ctx context.Context, xmlContent []byte) (*model.RevocationRequestInfo, error) {
+	return document.ParseRevocationRequestXml(ctx, c.adapter, xmlContent)
+}
+
+func (c DiadocClient) ParseSignatureRejectionXml(ctx context.Context, xmlContent []byte) (*model.SignatureRejectionInfo, error) {
+	return document.ParseSignatureRejectionXml(ctx, c.adapter, xmlContent)
+}
+
+func (c DiadocClient) ParseTitleXml(ctx context.Context, boxID string, documentTypeNamedID string, documentFunction string, documentVersion string, titleIndex int, xmlContent []byte) ([]byte, error) {
+	return document.ParseTitleXml(ctx, c.adapter, boxID, documentTypeNamedID, documentFunction, documentVersion, titleIndex, xmlContent)
+}
+
+///////////////////////////////////////////////////////////////////
+////////////////Ответные действия по документам////////////////////
+///////////////////////////////////////////////////////////////////
+
+func (c DiadocClient) AcceptDocument(ctx context.Context, boxID string, messageID string, documentID string, recipientTitle []byte, s signer.Signer) (*model.MessagePatch, error) {
+	return reply.AcceptDocument(ctx, c.adapter, boxID, messageID, documentID, recipientTitle, s)
+}
+
+func (c DiadocClient) RejectDocument(ctx context.Context, boxID string, messageID string, documentID string, reason string, s signer.Signer) (*model.MessagePatch, error) {
+	return reply.RejectDocument(ctx, c.adapter, boxID, messageID, documentID, reason, s)
+}
+
+func (c DiadocClient) RequestRevocation(ctx context.Context, boxID string, messageID string, documentID string, reason string, s signer.Signer) (*model.MessagePatch, error) {
+	return reply.RequestRevocation(ctx, c.adapter, boxID, messageID, documentID, reason, s)
+}
+
+func (c DiadocClient) AcceptRevocation(ctx context.Context, boxID string, messageID string, revocationRequestID string, s signer.Signer) (*model.MessagePatch, error) {
+	return reply.AcceptRevocation(ctx, c.adapter, boxID, messageID, revocationRequestID, s)
+}
+
+func (c DiadocClient) RejectRevocation(ctx context.Context, boxID string, messageID string, revocationRequestID string, reason string, s signer.Signer) (*model.MessagePatch, error) {
+	return reply.RejectRevocation(ctx, c.adapter, boxID, messageID, revocationRequestID, reason, s)
+}
+
+func (c DiadocClient) RequestCorrection(ctx context.Context, boxID string, messageID string, documentID string, reason string, s signer.Signer) (*model.MessagePatch, error) {
+	return reply.RequestCorrection(ctx, c.adapter, boxID, messageID, documentID, reason, s)
+}
+
+func (c DiadocClient) SendReceipt(ctx context.Context, boxID string, messageID string, attachmentID string, s signer.Signer) (*model.MessagePatch, error) {
+	return reply.SendReceipt(ctx, c.adapter, boxID, messageID, attachmentID, s)
+}
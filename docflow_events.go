@@ -0,0 +1,183 @@
+package diadocсlient
+
+import (
+	"context"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"github.com/DimaSSV/diadocclient/pkg/ticks"
+	"google.golang.org/protobuf/proto"
+	"time"
+)
+
+// NewTimeBasedFilter создает фильтр по интервалу времени. Нулевое значение from или to означает отсутствие границы
+func NewTimeBasedFilter(from time.Time, to time.Time, direction model.SortDirection) *model.TimeBasedFilter {
+	return &model.TimeBasedFilter{
+		FromTimestamp: ticks.FromTime(from).Timestamp(),
+		ToTimestamp:   ticks.FromTime(to).Timestamp(),
+		SortDirection: direction.Enum(),
+	}
+}
+
+// DocflowEventsOptions параметры обхода событий GetDocflowEvents
+type DocflowEventsOptions struct {
+	From time.Time
+	// To верхняя граница интервала; при непрерывном чтении (TailDocflowEvents) не задается
+	To                             time.Time
+	SortDirection                  model.SortDirection
+	AfterIndexKey                  []byte
+	PopulateDocuments              bool
+	InjectEntityContent            bool
+	PopulatePreviousDocumentStates bool
+	MessageTypes                   []string
+	DocumentDirections             []string
+	TypeNamedIDs                   []string
+	DepartmentID                   string
+	CounteragentBoxID              string
+	// Limit размер страницы, по умолчанию 100
+	Limit int32
+}
+
+func (o DocflowEventsOptions) request(afterIndexKey []byte) *model.GetDocflowEventsRequest {
+	direction := o.SortDirection
+	if direction == model.SortDirection_UnknownSortDirection {
+		direction = model.SortDirection_Ascending
+	}
+	request := &model.GetDocflowEventsRequest{
+		Filter:             NewTimeBasedFilter(o.From, o.To, direction),
+		AfterIndexKey:      afterIndexKey,
+		MessageTypes:       o.MessageTypes,
+		DocumentDirections: o.DocumentDirections,
+		TypeNamedIds:       o.TypeNamedIDs,
+	}
+	if o.PopulateDocuments {
+		request.PopulateDocuments = proto.Bool(true)
+	}
+	if o.InjectEntityContent {
+		request.InjectEntityContent = proto.Bool(true)
+	}
+	if o.PopulatePreviousDocumentStates {
+		request.PopulatePreviousDocumentStates = proto.Bool(true)
+	}
+	if o.DepartmentID != "" {
+		request.DepartmentId = proto.String(o.DepartmentID)
+	}
+	if o.CounteragentBoxID != "" {
+		request.CounteragentBoxId = proto.String(o.CounteragentBoxID)
+	}
+	if o.Limit != 0 {
+		request.Limit = proto.Int32(o.Limit)
+	}
+	return request
+}
+
+// DocflowEventIterator постранично обходит события документооборота за интервал времени:
+//
+//	it := client.IterateDocflowEvents(boxID, opts)
+//	for it.Next(ctx) {
+//		event := it.Event()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type DocflowEventIterator struct {
+	client        DiadocClient
+	boxID         string
+	options       DocflowEventsOptions
+	afterIndexKey []byte
+	page          []*model.DocflowEventV3
+	current       *model.DocflowEventV3
+	totalCount    int32
+	done          bool
+	err           error
+}
+
+func (c DiadocClient) IterateDocflowEvents(boxID string, options DocflowEventsOptions) *DocflowEventIterator {
+	return &DocflowEventIterator{
+		client:        c,
+		boxID:         boxID,
+		options:       options,
+		afterIndexKey: options.AfterIndexKey,
+	}
+}
+
+// Next переходит к следующему событию, при необходимости запрашивая следующую страницу.
+// Возвращает false по окончании событий или при ошибке
+func (it *DocflowEventIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.page) == 0 {
+		if it.done || !it.fetch(ctx) {
+			return false
+		}
+	}
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	it.afterIndexKey = it.current.GetIndexKey()
+	return true
+}
+
+func (it *DocflowEventIterator) fetch(ctx context.Context) bool {
+	response, err := it.client.GetDocflowEvents(ctx, it.boxID, it.options.request(it.afterIndexKey))
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.totalCount = response.GetTotalCount()
+	it.page = response.Events
+	if len(it.page) == 0 {
+		it.done = true
+		return false
+	}
+	return true
+}
+
+// Event возвращает текущее событие
+func (it *DocflowEventIterator) Event() *model.DocflowEventV3 {
+	return it.current
+}
+
+// IndexKey возвращает ключ последнего полученного события, с которого можно продолжить обход
+func (it *DocflowEventIterator) IndexKey() []byte {
+	return it.afterIndexKey
+}
+
+// TotalCount количество событий по данным последней страницы (точность зависит от TotalCountType)
+func (it *DocflowEventIterator) TotalCount() int32 {
+	return it.totalCount
+}
+
+func (it *DocflowEventIterator) Err() error {
+	return it.err
+}
+
+// TailDocflowEvents непрерывно читает новые события и передает их в handler. Когда новых событий нет,
+// выполняется пауза interval. Работа прекращается при отмене контекста или ошибке; возвращается ключ
+// последнего обработанного события, с которого можно продолжить
+func (c DiadocClient) TailDocflowEvents(
+	ctx context.Context,
+	boxID string,
+	options DocflowEventsOptions,
+	interval time.Duration,
+	handler func(ctx context.Context, event *model.DocflowEventV3) error,
+) ([]byte, error) {
+	options.To = time.Time{}
+	options.SortDirection = model.SortDirection_Ascending
+	afterIndexKey := options.AfterIndexKey
+	for {
+		options.AfterIndexKey = afterIndexKey
+		it := c.IterateDocflowEvents(boxID, options)
+		for it.Next(ctx) {
+			if err := handler(ctx, it.Event()); err != nil {
+				return afterIndexKey, err
+			}
+			afterIndexKey = it.IndexKey()
+		}
+		if err := it.Err(); err != nil {
+			return afterIndexKey, err
+		}
+		select {
+		case <-ctx.Done():
+			return afterIndexKey, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
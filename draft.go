@@ -0,0 +1,158 @@
+package diadocсlient
+
+import (
+	"context"
+	"fmt"
+	"github.com/DimaSSV/diadocclient/internal/service/document"
+	docfilter "github.com/DimaSSV/diadocclient/pkg/filter"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"github.com/DimaSSV/diadocclient/pkg/signer"
+	"google.golang.org/protobuf/proto"
+)
+
+// Draft черновик сообщения. Типовой порядок работы: CreateDraft → Prepare → Sign → Send
+type Draft struct {
+	client     DiadocClient
+	message    *model.Message
+	patched    map[string]*model.DocumentPatchedContent
+	signatures map[string]*model.DocumentSenderSignature
+}
+
+// CreateDraft создает черновик из сообщения post. Признак IsDraft выставляется автоматически
+func (c DiadocClient) CreateDraft(ctx context.Context, operationID string, post *model.MessageToPost) (*Draft, error) {
+	post.IsDraft = proto.Bool(true)
+	msg, err := c.PostMessage(ctx, operationID, post)
+	if err != nil {
+		return nil, err
+	}
+	return newDraft(c, msg), nil
+}
+
+func (c DiadocClient) GetDraft(ctx context.Context, boxID string, draftID string) (*Draft, error) {
+	msg, err := c.GetMessage(ctx, boxID, draftID, "", false, false)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.GetIsDraft() {
+		return nil, fmt.Errorf("сообщение %s не является черновиком", draftID)
+	}
+	return newDraft(c, msg), nil
+}
+
+// GetDrafts возвращает документы из черновиков ящика (категория фильтра Any.Draft)
+func (c DiadocClient) GetDrafts(ctx context.Context, boxID string, afterIndexKey string) (*model.DocumentList, error) {
+	filter := document.NewFilter(boxID, afterIndexKey)
+	filter.FilterCategory = docfilter.NewCategory(docfilter.AnyDocumentType, docfilter.Draft)
+	return c.GetDocuments(ctx, filter)
+}
+
+func (c DiadocClient) DeleteDraft(ctx context.Context, boxID string, draftID string) error {
+	return c.RecycleDraft(ctx, boxID, draftID)
+}
+
+func newDraft(c DiadocClient, msg *model.Message) *Draft {
+	return &Draft{
+		client:     c,
+		message:    msg,
+		patched:    make(map[string]*model.DocumentPatchedContent),
+		signatures: make(map[string]*model.DocumentSenderSignature),
+	}
+}
+
+func (d *Draft) ID() string {
+	return d.message.GetMessageId()
+}
+
+func (d *Draft) BoxID() string {
+	return d.message.GetFromBoxId()
+}
+
+func (d *Draft) Message() *model.Message {
+	return d.message
+}
+
+// Documents возвращает документы черновика (вложения верхнего уровня)
+func (d *Draft) Documents() []*model.Entity {
+	var result []*model.Entity
+	for _, entity := range d.message.Entities {
+		if entity.GetEntityType() == model.EntityType_TypeAttachment && entity.GetParentEntityId() == "" {
+			result = append(result, entity)
+		}
+	}
+	return result
+}
+
+// Prepare вызывает PrepareDocumentsToSign для всех документов черновика, чтобы Диадок
+// дописал в титулы сведения о подписанте. Для УПД и других документов формата 820
+// передаются extendedSigners, для остальных достаточно signer
+func (d *Draft) Prepare(ctx context.Context, signer *model.Signer, extendedSigners []*model.ExtendedSigner) error {
+	request := model.PrepareDocumentsToSignRequest{BoxId: proto.String(d.BoxID())}
+	for _, entity := range d.Documents() {
+		request.DraftDocuments = append(request.DraftDocuments, &model.DraftDocumentToPatch{
+			DocumentId: &model.DocumentId{
+				MessageId: proto.String(d.ID()),
+				EntityId:  entity.EntityId,
+			},
+			ToBoxId:        d.message.ToBoxId,
+			Signer:         signer,
+			ExtendedSigner: extendedSigners,
+		})
+	}
+	response, err := d.client.PrepareDocumentsToSign(ctx, &request)
+	if err != nil {
+		return err
+	}
+	for _, content := range response.DocumentPatchedContents {
+		d.patched[content.GetDocumentId().GetEntityId()] = content
+	}
+	return nil
+}
+
+// Sign подписывает документы черновика. Если черновик был подготовлен через Prepare,
+// подписывается измененное содержимое документа
+func (d *Draft) Sign(ctx context.Context, s signer.Signer) error {
+	for _, entity := range d.Documents() {
+		signature := model.DocumentSenderSignature{ParentEntityId: entity.EntityId}
+		contentID := entity.GetEntityId()
+		data := entity.GetContent().GetData()
+		if patched, ok := d.patched[entity.GetEntityId()]; ok {
+			signature.PatchedContentId = patched.PatchedContentId
+			contentID = patched.GetPatchedContentId()
+			data = patched.Content
+		}
+		var err error
+		if data == nil {
+			data, err = d.client.GetEntityContent(ctx, d.BoxID(), d.ID(), contentID)
+			if err != nil {
+				return err
+			}
+		}
+		signature.Signature, err = s.Sign(ctx, data)
+		if err != nil {
+			return err
+		}
+		d.signatures[entity.GetEntityId()] = &signature
+	}
+	return nil
+}
+
+// Send отправляет черновик с подписями, полученными в Sign
+func (d *Draft) Send(ctx context.Context, operationID string) (*model.Message, error) {
+	send := model.DraftToSend{
+		BoxId:   proto.String(d.BoxID()),
+		DraftId: proto.String(d.ID()),
+		ToBoxId: d.message.ToBoxId,
+	}
+	for _, entity := range d.Documents() {
+		signature, ok := d.signatures[entity.GetEntityId()]
+		if !ok {
+			return nil, fmt.Errorf("документ %s черновика не подписан", entity.GetEntityId())
+		}
+		send.DocumentSignatures = append(send.DocumentSignatures, signature)
+	}
+	return d.client.SendDraft(ctx, operationID, &send)
+}
+
+func (d *Draft) Delete(ctx context.Context) error {
+	return d.client.DeleteDraft(ctx, d.BoxID(), d.ID())
+}
@@ -0,0 +1,416 @@
+package diadocсlient
+
+import (
+	"context"
+	"fmt"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+)
+
+// headDepartmentID идентификатор головного подразделения
+const headDepartmentID = "00000000-0000-0000-0000-000000000000"
+
+// EmployeeSpec желаемый состав сотрудников ящиков. Описывается в YAML или JSON:
+//
+//	boxes:
+//	  - boxId: 3f2a...@diadoc.ru
+//	    deleteUnlisted: true
+//	    employees:
+//	      - login: ivanov@example.ru
+//	        lastName: Иванов
+//	        firstName: Иван
+//	        position: Главный бухгалтер
+//	        departmentId: 00000000-0000-0000-0000-000000000000
+//	        documentAccessLevel: AllDocuments
+//	        isAdministrator: false
+//	        actions: {SignDocuments: true, CreateDocuments: true}
+//	        subscriptions: {InboundDocuments: true}
+//
+// Незаполненные поля не сравниваются и не изменяются у существующих сотрудников
+type EmployeeSpec struct {
+	Boxes []BoxEmployeesSpec `json:"boxes" yaml:"boxes"`
+}
+
+type BoxEmployeesSpec struct {
+	BoxID string `json:"boxId" yaml:"boxId"`
+	// DeleteUnlisted удалять сотрудников ящика, отсутствующих в спецификации (кроме текущего пользователя)
+	DeleteUnlisted bool                   `json:"deleteUnlisted" yaml:"deleteUnlisted"`
+	Employees      []EmployeeDesiredState `json:"employees" yaml:"employees"`
+}
+
+// EmployeeDesiredState желаемое состояние сотрудника. Сотрудник определяется по UserID, а если он не задан - по Login
+type EmployeeDesiredState struct {
+	UserID              string          `json:"userId,omitempty" yaml:"userId,omitempty"`
+	Login               string          `json:"login,omitempty" yaml:"login,omitempty"`
+	LastName            string          `json:"lastName,omitempty" yaml:"lastName,omitempty"`
+	FirstName           string          `json:"firstName,omitempty" yaml:"firstName,omitempty"`
+	MiddleName          string          `json:"middleName,omitempty" yaml:"middleName,omitempty"`
+	Position            *string         `json:"position,omitempty" yaml:"position,omitempty"`
+	DepartmentID        *string         `json:"departmentId,omitempty" yaml:"departmentId,omitempty"`
+	IsAdministrator     *bool           `json:"isAdministrator,omitempty" yaml:"isAdministrator,omitempty"`
+	DocumentAccessLevel string          `json:"documentAccessLevel,omitempty" yaml:"documentAccessLevel,omitempty"`
+	SelectedDepartments []string        `json:"selectedDepartmentIds,omitempty" yaml:"selectedDepartmentIds,omitempty"`
+	Actions             map[string]bool `json:"actions,omitempty" yaml:"actions,omitempty"`
+	Subscriptions       map[string]bool `json:"subscriptions,omitempty" yaml:"subscriptions,omitempty"`
+	CanBeInvitedForChat *bool           `json:"canBeInvitedForChat,omitempty" yaml:"canBeInvitedForChat,omitempty"`
+}
+
+// ParseEmployeeSpec разбирает спецификацию в формате YAML или JSON (JSON является подмножеством YAML)
+func ParseEmployeeSpec(data []byte) (*EmployeeSpec, error) {
+	spec := &EmployeeSpec{}
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, err
+	}
+	for _, box := range spec.Boxes {
+		if box.BoxID == "" {
+			return nil, fmt.Errorf("в спецификации не указан boxId")
+		}
+		for _, desired := range box.Employees {
+			if desired.UserID == "" && desired.Login == "" {
+				return nil, fmt.Errorf("ящик %s: для сотрудника не указан ни userId, ни login", box.BoxID)
+			}
+			if desired.DocumentAccessLevel != "" {
+				if _, ok := model.DocumentAccessLevel_value[desired.DocumentAccessLevel]; !ok {
+					return nil, fmt.Errorf("ящик %s, сотрудник %s: неизвестный уровень доступа %q", box.BoxID, desired.key(), desired.DocumentAccessLevel)
+				}
+			}
+		}
+	}
+	return spec, nil
+}
+
+func (d EmployeeDesiredState) key() string {
+	if d.UserID != "" {
+		return d.UserID
+	}
+	return d.Login
+}
+
+type EmployeeOperationKind string
+
+const (
+	EmployeeCreate              EmployeeOperationKind = "create"
+	EmployeeUpdate              EmployeeOperationKind = "update"
+	EmployeeUpdateSubscriptions EmployeeOperationKind = "update-subscriptions"
+	EmployeeDelete              EmployeeOperationKind = "delete"
+)
+
+// EmployeeOperation одно изменение плана
+type EmployeeOperation struct {
+	Kind   EmployeeOperationKind
+	BoxID  string
+	UserID string
+	Login  string
+	// Changes описание изменений для вывода плана
+	Changes []string
+
+	create        *model.EmployeeToCreate
+	update        *model.EmployeeToUpdate
+	subscriptions *model.SubscriptionsToUpdate
+}
+
+// EmployeePlan список изменений, приводящих сотрудников ящиков к спецификации
+type EmployeePlan struct {
+	Operations []EmployeeOperation
+}
+
+// String выводит план в читаемом виде (для пробного запуска)
+func (p *EmployeePlan) String() string {
+	if len(p.Operations) == 0 {
+		return "Изменений нет\n"
+	}
+	var buf strings.Builder
+	for _, op := range p.Operations {
+		name := op.Login
+		if name == "" {
+			name = op.UserID
+		}
+		fmt.Fprintf(&buf, "%s %s %s\n", op.Kind, op.BoxID, name)
+		for _, change := range op.Changes {
+			fmt.Fprintf(&buf, "    %s\n", change)
+		}
+	}
+	return buf.String()
+}
+
+// PlanEmployees сравнивает спецификацию с текущими сотрудниками ящиков и возвращает план изменений
+func (c DiadocClient) PlanEmployees(ctx context.Context, spec *EmployeeSpec) (*EmployeePlan, error) {
+	me, err := c.GetMyUserV2(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan := &EmployeePlan{}
+	for _, box := range spec.Boxes {
+		employees, err := c.ListAllEmployees(ctx, box.BoxID)
+		if err != nil {
+			return nil, err
+		}
+		matched := make(map[string]bool)
+		for _, desired := range box.Employees {
+			current := findEmployee(employees, desired)
+			if current == nil {
+				op, err := createOperation(box.BoxID, desired)
+				if err != nil {
+					return nil, err
+				}
+				plan.Operations = append(plan.Operations, op)
+				continue
+			}
+			userID := current.GetUser().GetUserId()
+			matched[userID] = true
+			if op, ok := updateOperation(box.BoxID, current, desired); ok {
+				plan.Operations = append(plan.Operations, op)
+			}
+			if len(desired.Subscriptions) > 0 {
+				subscriptions, err := c.GetSubscriptions(ctx, box.BoxID, userID)
+				if err != nil {
+					return nil, err
+				}
+				if op, ok := subscriptionsOperation(box.BoxID, current, subscriptions, desired.Subscriptions); ok {
+					plan.Operations = append(plan.Operations, op)
+				}
+			}
+		}
+		if box.DeleteUnlisted {
+			for _, employee := range employees {
+				userID := employee.GetUser().GetUserId()
+				if matched[userID] || userID == me.GetUserId() {
+					continue
+				}
+				plan.Operations = append(plan.Operations, EmployeeOperation{
+					Kind:   EmployeeDelete,
+					BoxID:  box.BoxID,
+					UserID: userID,
+					Login:  employee.GetUser().GetLogin(),
+				})
+			}
+		}
+	}
+	return plan, nil
+}
+
+// ApplyEmployeePlan выполняет план. Выполнение останавливается на первой ошибке;
+// возвращается количество выполненных операций
+func (c DiadocClient) ApplyEmployeePlan(ctx context.Context, plan *EmployeePlan) (int, error) {
+	for i, op := range plan.Operations {
+		var err error
+		switch op.Kind {
+		case EmployeeCreate:
+			var employee *model.Employee
+			employee, err = c.CreateEmployee(ctx, op.BoxID, op.create)
+			if err == nil && op.subscriptions != nil {
+				_, err = c.UpdateSubscriptions(ctx, op.BoxID, employee.GetUser().GetUserId(), op.subscriptions)
+			}
+		case EmployeeUpdate:
+			_, err = c.UpdateEmployee(ctx, op.BoxID, op.UserID, op.update)
+		case EmployeeUpdateSubscriptions:
+			_, err = c.UpdateSubscriptions(ctx, op.BoxID, op.UserID, op.subscriptions)
+		case EmployeeDelete:
+			err = c.DeleteEmployee(ctx, op.BoxID, op.UserID)
+		}
+		if err != nil {
+			return i, fmt.Errorf("%s %s %s: %w", op.Kind, op.BoxID, op.Login, err)
+		}
+	}
+	return len(plan.Operations), nil
+}
+
+func findEmployee(employees []*model.Employee, desired EmployeeDesiredState) *model.Employee {
+	for _, employee := range employees {
+		user := employee.GetUser()
+		if desired.UserID != "" && user.GetUserId() == desired.UserID ||
+			desired.UserID == "" && strings.EqualFold(user.GetLogin(), desired.Login) {
+			return employee
+		}
+	}
+	return nil
+}
+
+func createOperation(boxID string, desired EmployeeDesiredState) (EmployeeOperation, error) {
+	if desired.Login == "" || desired.LastName == "" || desired.FirstName == "" {
+		return EmployeeOperation{}, fmt.Errorf("ящик %s, сотрудник %s: для создания нужны login, lastName и firstName", boxID, desired.key())
+	}
+	if desired.DocumentAccessLevel == "" {
+		return EmployeeOperation{}, fmt.Errorf("ящик %s, сотрудник %s: для создания нужен documentAccessLevel", boxID, desired.key())
+	}
+	permissions := &model.EmployeePermissions{
+		UserDepartmentId:      proto.String(headDepartmentID),
+		IsAdministrator:       proto.Bool(desired.IsAdministrator != nil && *desired.IsAdministrator),
+		DocumentAccessLevel:   model.DocumentAccessLevel(model.DocumentAccessLevel_value[desired.DocumentAccessLevel]).Enum(),
+		SelectedDepartmentIds: desired.SelectedDepartments,
+		Actions:               employeeActions(desired.Actions),
+	}
+	if desired.DepartmentID != nil {
+		permissions.UserDepartmentId = proto.String(*desired.DepartmentID)
+	}
+	create := &model.EmployeeToCreate{
+		Credentials: &model.EmployeeToCreateCredentials{
+			Login: &model.EmployeeToCreateByLogin{
+				Login: proto.String(desired.Login),
+				FullName: &model.FullName{
+					LastName:   proto.String(desired.LastName),
+					FirstName:  proto.String(desired.FirstName),
+					MiddleName: optionalString(desired.MiddleName),
+				},
+			},
+		},
+		CanBeInvitedForChat: proto.Bool(desired.CanBeInvitedForChat != nil && *desired.CanBeInvitedForChat),
+		Permissions:         permissions,
+	}
+	op := EmployeeOperation{
+		Kind:    EmployeeCreate,
+		BoxID:   boxID,
+		Login:   desired.Login,
+		create:  create,
+		Changes: []string{fmt.Sprintf("documentAccessLevel: %s", desired.DocumentAccessLevel)},
+	}
+	if desired.Position != nil {
+		create.Position = proto.String(*desired.Position)
+		op.Changes = append(op.Changes, fmt.Sprintf("position: %s", *desired.Position))
+	}
+	if len(desired.Subscriptions) > 0 {
+		op.subscriptions = subscriptionsToUpdate(desired.Subscriptions)
+		op.Changes = append(op.Changes, "subscriptions: "+formatFlags(desired.Subscriptions))
+	}
+	return op, nil
+}
+
+func updateOperation(boxID string, current *model.Employee, desired EmployeeDesiredState) (EmployeeOperation, bool) {
+	permissions := current.GetPermissions()
+	patch := &model.EmployeePermissionsPatch{}
+	update := &model.EmployeeToUpdate{}
+	var changes []string
+	changed := false
+	if desired.Position != nil && *desired.Position != current.GetPosition() {
+		update.Position = &model.EmployeePositionPatch{Position: proto.String(*desired.Position)}
+		changes = append(changes, fmt.Sprintf("position: %q -> %q", current.GetPosition(), *desired.Position))
+	}
+	if desired.CanBeInvitedForChat != nil && *desired.CanBeInvitedForChat != current.GetCanBeInvitedForChat() {
+		update.CanBeInvitedForChat = &model.EmployeeCanBeInvitedForChatPatch{CanBeInvitedForChat: proto.Bool(*desired.CanBeInvitedForChat)}
+		changes = append(changes, fmt.Sprintf("canBeInvitedForChat: %t -> %t", current.GetCanBeInvitedForChat(), *desired.CanBeInvitedForChat))
+	}
+	if desired.DepartmentID != nil && *desired.DepartmentID != permissions.GetUserDepartmentId() {
+		patch.Department = &model.EmployeeDepartmentPatch{DepartmentId: proto.String(*desired.DepartmentID)}
+		changes = append(changes, fmt.Sprintf("departmentId: %s -> %s", permissions.GetUserDepartmentId(), *desired.DepartmentID))
+		changed = true
+	}
+	if desired.IsAdministrator != nil && *desired.IsAdministrator != permissions.GetIsAdministrator() {
+		patch.IsAdministrator = &model.EmployeeIsAdministratorPatch{IsAdministrator: proto.Bool(*desired.IsAdministrator)}
+		changes = append(changes, fmt.Sprintf("isAdministrator: %t -> %t", permissions.GetIsAdministrator(), *desired.IsAdministrator))
+		changed = true
+	}
+	if desired.DocumentAccessLevel != "" && desired.DocumentAccessLevel != permissions.GetDocumentAccessLevel().String() {
+		level := model.DocumentAccessLevel(model.DocumentAccessLevel_value[desired.DocumentAccessLevel])
+		patch.DocumentAccessLevel = &model.EmployeeDocumentAccessLevelPatch{DocumentAccessLevel: level.Enum()}
+		changes = append(changes, fmt.Sprintf("documentAccessLevel: %s -> %s", permissions.GetDocumentAccessLevel(), desired.DocumentAccessLevel))
+		changed = true
+	}
+	if desired.SelectedDepartments != nil && !sameStringSet(desired.SelectedDepartments, permissions.GetSelectedDepartmentIds()) {
+		patch.SelectedDepartments = &model.EmployeeSelectedDepartmentsPatch{SelectedDepartmentIds: desired.SelectedDepartments}
+		changes = append(changes, fmt.Sprintf("selectedDepartmentIds: %v -> %v", permissions.GetSelectedDepartmentIds(), desired.SelectedDepartments))
+		changed = true
+	}
+	currentActions := make(map[string]bool)
+	for _, action := range permissions.GetActions() {
+		currentActions[action.GetName()] = action.GetIsAllowed()
+	}
+	for _, name := range sortedKeys(desired.Actions) {
+		if allowed := desired.Actions[name]; currentActions[name] != allowed {
+			patch.Actions = append(patch.Actions, &model.EmployeeAction{Name: proto.String(name), IsAllowed: proto.Bool(allowed)})
+			changes = append(changes, fmt.Sprintf("action %s: %t -> %t", name, currentActions[name], allowed))
+			changed = true
+		}
+	}
+	if changed {
+		update.Permissions = patch
+	}
+	if len(changes) == 0 {
+		return EmployeeOperation{}, false
+	}
+	return EmployeeOperation{
+		Kind:    EmployeeUpdate,
+		BoxID:   boxID,
+		UserID:  current.GetUser().GetUserId(),
+		Login:   current.GetUser().GetLogin(),
+		Changes: changes,
+		update:  update,
+	}, true
+}
+
+func subscriptionsOperation(boxID string, current *model.Employee, subscriptions *model.EmployeeSubscriptions, desired map[string]bool) (EmployeeOperation, bool) {
+	currentFlags := make(map[string]bool)
+	for _, subscription := range subscriptions.GetSubscriptions() {
+		currentFlags[subscription.GetId()] = subscription.GetIsSubscribed()
+	}
+	changedFlags := make(map[string]bool)
+	var changes []string
+	for _, id := range sortedKeys(desired) {
+		if currentFlags[id] != desired[id] {
+			changedFlags[id] = desired[id]
+			changes = append(changes, fmt.Sprintf("subscription %s: %t -> %t", id, currentFlags[id], desired[id]))
+		}
+	}
+	if len(changes) == 0 {
+		return EmployeeOperation{}, false
+	}
+	return EmployeeOperation{
+		Kind:          EmployeeUpdateSubscriptions,
+		BoxID:         boxID,
+		UserID:        current.GetUser().GetUserId(),
+		Login:         current.GetUser().GetLogin(),
+		Changes:       changes,
+		subscriptions: subscriptionsToUpdate(changedFlags),
+	}, true
+}
+
+func employeeActions(actions map[string]bool) []*model.EmployeeAction {
+	var result []*model.EmployeeAction
+	for _, name := range sortedKeys(actions) {
+		result = append(result, &model.EmployeeAction{Name: proto.String(name), IsAllowed: proto.Bool(actions[name])})
+	}
+	return result
+}
+
+func subscriptionsToUpdate(flags map[string]bool) *model.SubscriptionsToUpdate {
+	update := &model.SubscriptionsToUpdate{}
+	for _, id := range sortedKeys(flags) {
+		update.Subscriptions = append(update.Subscriptions, &model.Subscription{Id: proto.String(id), IsSubscribed: proto.Bool(flags[id])})
+	}
+	return update
+}
+
+func formatFlags(flags map[string]bool) string {
+	var parts []string
+	for _, key := range sortedKeys(flags) {
+		parts = append(parts, fmt.Sprintf("%s=%t", key, flags[key]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sameStringSet(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, value := range a {
+		set[value] = true
+	}
+	for _, value := range b {
+		if !set[value] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,499 @@
+package diadocсlient
+
+import (
+	"context"
+	"github.com/DimaSSV/diadocclient/pkg/filter"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"github.com/DimaSSV/diadocclient/pkg/ticks"
+	"time"
+)
+
+// EventInfo общие сведения о событии, из которого получена типизированная нагрузка
+type EventInfo struct {
+	BoxID     string
+	EventID   string
+	IndexKey  string
+	MessageID string
+	Timestamp ticks.Ticks
+	Event     *model.BoxEvent
+}
+
+// DocumentEvent новый документ в ящике
+type DocumentEvent struct {
+	EventInfo
+	// Entity сущность документа, сведения о документе в Entity.DocumentInfo
+	Entity            *model.Entity
+	CounteragentBoxID string
+}
+
+// ReplyEvent ответное действие по документу: подпись, отказ, аннулирование, резолюция и т.п.
+type ReplyEvent struct {
+	EventInfo
+	// Entity сущность ответа (служебный документ или подпись)
+	Entity *model.Entity
+	// Parent сущность, к которой непосредственно относится ответ
+	// (для подписи под предложением об аннулировании - само предложение)
+	Parent *model.Entity
+	// Document сущность исходного документа
+	Document *model.Entity
+	// IsOurs ответ сформирован текущим ящиком
+	IsOurs bool
+}
+
+type (
+	DocumentHandler func(ctx context.Context, e DocumentEvent) error
+	ReplyHandler    func(ctx context.Context, e ReplyEvent) error
+	BoxEventHandler func(ctx context.Context, e *model.BoxEvent) error
+)
+
+// EventDispatcher разбирает события GetNewEvents и вызывает зарегистрированные обработчики.
+// Обработчики ответов вызываются только для действий контрагента (и сотрудников для резолюций),
+// кроме OnReply, который получает все ответы
+type EventDispatcher struct {
+	client   DiadocClient
+	boxID    string
+	interval time.Duration
+
+	onEvent               []BoxEventHandler
+	onInboundDocument     []DocumentHandler
+	onOutboundDocument    []DocumentHandler
+	onReply               []ReplyHandler
+	onDocumentSigned      []ReplyHandler
+	onSignatureRejected   []ReplyHandler
+	onRevocationRequested []ReplyHandler
+	onRevocationAccepted  []ReplyHandler
+	onRevocationRejected  []ReplyHandler
+	onCorrectionRequested []ReplyHandler
+	onResolutionRequested []ReplyHandler
+	onResolution          []ReplyHandler
+	onReceiptRequired     []ReplyHandler
+}
+
+func (c DiadocClient) NewEventDispatcher(boxID string) *EventDispatcher {
+	return &EventDispatcher{
+		client:   c,
+		boxID:    boxID,
+		interval: time.Minute,
+	}
+}
+
+// PollInterval задает паузу между запросами, когда новых событий нет (по умолчанию минута)
+func (d *EventDispatcher) PollInterval(interval time.Duration) *EventDispatcher {
+	d.interval = interval
+	return d
+}
+
+// OnEvent вызывается для каждого события до типизированных обработчиков
+func (d *EventDispatcher) OnEvent(h BoxEventHandler) *EventDispatcher {
+	d.onEvent = append(d.onEvent, h)
+	return d
+}
+
+// OnInboundDocument вызывается для каждого документа во входящем сообщении
+func (d *EventDispatcher) OnInboundDocument(h DocumentHandler) *EventDispatcher {
+	d.onInboundDocument = append(d.onInboundDocument, h)
+	return d
+}
+
+// OnOutboundDocument вызывается для каждого документа в отправленном сообщении
+func (d *EventDispatcher) OnOutboundDocument(h DocumentHandler) *EventDispatcher {
+	d.onOutboundDocument = append(d.onOutboundDocument, h)
+	return d
+}
+
+// OnReply вызывается для каждой сущности, добавленной к документу, включая собственные действия
+func (d *EventDispatcher) OnReply(h ReplyHandler) *EventDispatcher {
+	d.onReply = append(d.onReply, h)
+	return d
+}
+
+// OnDocumentSigned вызывается, когда контрагент подписал документ или титул
+func (d *EventDispatcher) OnDocumentSigned(h ReplyHandler) *EventDispatcher {
+	d.onDocumentSigned = append(d.onDocumentSigned, h)
+	return d
+}
+
+// OnSignatureRejected вызывается, когда контрагент отказал в подписи документа
+func (d *EventDispatcher) OnSignatureRejected(h ReplyHandler) *EventDispatcher {
+	d.onSignatureRejected = append(d.onSignatureRejected, h)
+	return d
+}
+
+// OnRevocationRequested вызывается, когда контрагент предложил аннулировать документ
+func (d *EventDispatcher) OnRevocationRequested(h ReplyHandler) *EventDispatcher {
+	d.onRevocationRequested = append(d.onRevocationRequested, h)
+	return d
+}
+
+// OnRevocationAccepted вызывается, когда контрагент подписал наше предложение об аннулировании
+func (d *EventDispatcher) OnRevocationAccepted(h ReplyHandler) *EventDispatcher {
+	d.onRevocationAccepted = append(d.onRevocationAccepted, h)
+	return d
+}
+
+// OnRevocationRejected вызывается, когда контрагент отказал в аннулировании
+func (d *EventDispatcher) OnRevocationRejected(h ReplyHandler) *EventDispatcher {
+	d.onRevocationRejected = append(d.onRevocationRejected, h)
+	return d
+}
+
+// OnCorrectionRequested вызывается, когда контрагент запросил уточнение документа
+func (d *EventDispatcher) OnCorrectionRequested(h ReplyHandler) *EventDispatcher {
+	d.onCorrectionRequested = append(d.onCorrectionRequested, h)
+	return d
+}
+
+// OnResolutionRequested вызывается при запросе согласования или подписи документа (Entity.ResolutionRequestInfo)
+func (d *EventDispatcher) OnResolutionRequested(h ReplyHandler) *EventDispatcher {
+	d.onResolutionRequested = append(d.onResolutionRequested, h)
+	return d
+}
+
+// OnResolution вызывается при согласовании или отказе в согласовании (Entity.ResolutionInfo)
+func (d *EventDispatcher) OnResolution(h ReplyHandler) *EventDispatcher {
+	d.onResolution = append(d.onResolution, h)
+	return d
+}
+
+// OnReceiptRequired вызывается для сущностей контрагента, на которые требуется отправить извещение о получении
+// (см. DiadocClient.SendReceipt). Для самого документа Parent и Document равны nil
+func (d *EventDispatcher) OnReceiptRequired(h ReplyHandler) *EventDispatcher {
+	d.onReceiptRequired = append(d.onReceiptRequired, h)
+	return d
+}
+
+// Run опрашивает GetNewEvents начиная с afterIndexKey до отмены контекста или ошибки обработчика.
+// Возвращает IndexKey последнего полностью обработанного события, с которого можно продолжить
+func (d *EventDispatcher) Run(ctx context.Context, afterIndexKey string) (string, error) {
+	for {
+		lastIndexKey, count, err := d.Poll(ctx, afterIndexKey)
+		afterIndexKey = lastIndexKey
+		if err != nil {
+			return afterIndexKey, err
+		}
+		if count > 0 {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return afterIndexKey, ctx.Err()
+		case <-time.After(d.interval):
+		}
+	}
+}
+
+// Poll получает одну страницу событий после afterIndexKey и обрабатывает их.
+// Возвращает IndexKey последнего обработанного события и количество полученных событий
+func (d *EventDispatcher) Poll(ctx context.Context, afterIndexKey string) (string, int, error) {
+	list, err := d.client.GetNewEvents(ctx, d.boxID, filter.Events{AfterIndexKey: afterIndexKey})
+	if err != nil {
+		return afterIndexKey, 0, err
+	}
+	for _, event := range list.Events {
+		if err = d.Dispatch(ctx, event); err != nil {
+			return afterIndexKey, len(list.Events), err
+		}
+		afterIndexKey = event.GetIndexKey()
+	}
+	return afterIndexKey, len(list.Events), nil
+}
+
+// Dispatch разбирает одно событие и вызывает обработчики
+func (d *EventDispatcher) Dispatch(ctx context.Context, event *model.BoxEvent) error {
+	for _, h := range d.onEvent {
+		if err := h(ctx, event); err != nil {
+			return err
+		}
+	}
+	r := &eventResolver{dispatcher: d, event: event}
+	if msg := event.GetMessage(); msg != nil {
+		if msg.GetIsDraft() {
+			return nil
+		}
+		r.messageID = msg.GetMessageId()
+		r.entities = msg.Entities
+		return d.dispatchEntities(ctx, r, ticks.Ticks(msg.GetTimestampTicks()), true)
+	}
+	if patch := event.GetPatch(); patch != nil {
+		if patch.GetForDraft() {
+			return nil
+		}
+		r.messageID = patch.GetMessageId()
+		r.entities = patch.Entities
+		return d.dispatchEntities(ctx, r, ticks.Ticks(patch.GetTimestampTicks()), false)
+	}
+	return nil
+}
+
+func (d *EventDispatcher) dispatchEntities(ctx context.Context, r *eventResolver, timestamp ticks.Ticks, isMessage bool) error {
+	info := EventInfo{
+		BoxID:     d.boxID,
+		EventID:   r.event.GetEventId(),
+		IndexKey:  r.event.GetIndexKey(),
+		MessageID: r.messageID,
+		Timestamp: timestamp,
+		Event:     r.event,
+	}
+	for _, entity := range r.entities {
+		if entity.GetParentEntityId() == "" {
+			if !isMessage || entity.GetEntityType() != model.EntityType_TypeAttachment {
+				continue
+			}
+			if err := d.dispatchDocument(ctx, r, info, entity); err != nil {
+				return err
+			}
+			continue
+		}
+		parent, err := r.entity(ctx, entity.GetParentEntityId())
+		if err != nil {
+			return err
+		}
+		document, err := r.document(ctx, parent)
+		if err != nil {
+			return err
+		}
+		isOurs, err := r.authoredByUs(ctx, entity, isMessage)
+		if err != nil {
+			return err
+		}
+		e := ReplyEvent{
+			EventInfo: info,
+			Entity:    entity,
+			Parent:    parent,
+			Document:  document,
+			IsOurs:    isOurs,
+		}
+		// Подписи отправителя в новом сообщении не являются ответом на документ
+		if isMessage && entity.GetEntityType() == model.EntityType_TypeSignature {
+			err = callReplyHandlers(ctx, d.onReply, e)
+		} else {
+			err = d.dispatchReply(ctx, r, e)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *EventDispatcher) dispatchDocument(ctx context.Context, r *eventResolver, info EventInfo, entity *model.Entity) error {
+	msg := r.event.GetMessage()
+	e := DocumentEvent{EventInfo: info, Entity: entity}
+	handlers := d.onInboundDocument
+	if msg.GetFromBoxId() == d.boxID {
+		e.CounteragentBoxID = msg.GetToBoxId()
+		handlers = d.onOutboundDocument
+	} else {
+		e.CounteragentBoxID = msg.GetFromBoxId()
+	}
+	for _, h := range handlers {
+		if err := h(ctx, e); err != nil {
+			return err
+		}
+	}
+	if entity.GetNeedReceipt() && msg.GetFromBoxId() != d.boxID {
+		return callReplyHandlers(ctx, d.onReceiptRequired, ReplyEvent{EventInfo: info, Entity: entity})
+	}
+	return nil
+}
+
+func (d *EventDispatcher) dispatchReply(ctx context.Context, r *eventResolver, e ReplyEvent) error {
+	if err := callReplyHandlers(ctx, d.onReply, e); err != nil {
+		return err
+	}
+	var handlers []ReplyHandler
+	parentType := e.Parent.GetAttachmentType()
+	switch e.Entity.GetAttachmentType() {
+	case model.AttachmentType_ResolutionRequest:
+		handlers = d.onResolutionRequested
+	case model.AttachmentType_Resolution:
+		handlers = d.onResolution
+	}
+	if !e.IsOurs {
+		switch e.Entity.GetAttachmentType() {
+		case model.AttachmentType_XmlSignatureRejectionType, model.AttachmentType_SignatureRequestRejectionType:
+			if parentType == model.AttachmentType_RevocationRequestType {
+				handlers = d.onRevocationRejected
+			} else {
+				handlers = d.onSignatureRejected
+			}
+		case model.AttachmentType_RevocationRequestType:
+			handlers = d.onRevocationRequested
+		case model.AttachmentType_InvoiceCorrectionRequestType:
+			handlers = d.onCorrectionRequested
+		}
+		if e.Entity.GetEntityType() == model.EntityType_TypeSignature && !e.Entity.GetIsApprovementSignature() {
+			if parentType == model.AttachmentType_RevocationRequestType {
+				requestedByUs, err := r.revocationRequestedByUs(ctx, e.Parent)
+				if err != nil {
+					return err
+				}
+				// Подпись под предложением контрагента - это его собственная подпись предложения, а не согласие
+				if requestedByUs {
+					handlers = d.onRevocationAccepted
+				}
+			} else if e.Parent.GetEntityType() == model.EntityType_TypeAttachment && !isServiceAttachment(parentType) {
+				handlers = d.onDocumentSigned
+			}
+		}
+	}
+	if err := callReplyHandlers(ctx, handlers, e); err != nil {
+		return err
+	}
+	if e.Entity.GetNeedReceipt() && !e.IsOurs {
+		return callReplyHandlers(ctx, d.onReceiptRequired, e)
+	}
+	return nil
+}
+
+func callReplyHandlers(ctx context.Context, handlers []ReplyHandler, e ReplyEvent) error {
+	for _, h := range handlers {
+		if err := h(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isServiceAttachment служебные документы, подпись под которыми не означает подписания документа получателем
+func isServiceAttachment(t model.AttachmentType) bool {
+	switch t {
+	case model.AttachmentType_ReceiptType,
+		model.AttachmentType_InvoiceReceiptType,
+		model.AttachmentType_InvoiceConfirmationType,
+		model.AttachmentType_InvoiceCorrectionRequestType,
+		model.AttachmentType_XmlSignatureRejectionType,
+		model.AttachmentType_SignatureRequestRejectionType,
+		model.AttachmentType_Resolution,
+		model.AttachmentType_ResolutionRequest,
+		model.AttachmentType_ResolutionRequestDenialType,
+		model.AttachmentType_AttachmentCommentType,
+		model.AttachmentType_RoamingNotificationType,
+		model.AttachmentType_RoamingConfirmationType,
+		model.AttachmentType_DeliveryFailureNotificationType,
+		model.AttachmentType_SignatureVerificationReportType:
+		return true
+	}
+	return false
+}
+
+// eventResolver находит сущности, на которые ссылается событие. Сущности, отсутствующие в событии,
+// берутся из сообщения целиком (запрашивается не более одного раза на событие)
+type eventResolver struct {
+	dispatcher *EventDispatcher
+	event      *model.BoxEvent
+	messageID  string
+	entities   []*model.Entity
+	message    *model.Message
+}
+
+func (r *eventResolver) entity(ctx context.Context, entityID string) (*model.Entity, error) {
+	for _, entity := range r.entities {
+		if entity.GetEntityId() == entityID {
+			return entity, nil
+		}
+	}
+	if err := r.loadMessage(ctx); err != nil {
+		return nil, err
+	}
+	for _, entity := range r.message.Entities {
+		if entity.GetEntityId() == entityID {
+			return entity, nil
+		}
+	}
+	return nil, nil
+}
+
+// document поднимается по ParentEntityId до сущности документа
+func (r *eventResolver) document(ctx context.Context, entity *model.Entity) (*model.Entity, error) {
+	for entity != nil && entity.GetParentEntityId() != "" {
+		var err error
+		entity, err = r.entity(ctx, entity.GetParentEntityId())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entity, nil
+}
+
+// loadMessage запрашивает сообщение целиком, если оно еще не загружено
+func (r *eventResolver) loadMessage(ctx context.Context) error {
+	if r.message != nil {
+		return nil
+	}
+	msg, err := r.dispatcher.client.GetMessage(ctx, r.dispatcher.boxID, r.messageID, "", false, false)
+	if err != nil {
+		return err
+	}
+	r.message = msg
+	return nil
+}
+
+// firstSignature возвращает самую раннюю подпись под сущностью parentID среди entities
+func firstSignature(entities []*model.Entity, parentID string) *model.Entity {
+	var first *model.Entity
+	for _, entity := range entities {
+		if entity.GetEntityType() != model.EntityType_TypeSignature || entity.GetParentEntityId() != parentID {
+			continue
+		}
+		if first == nil || entity.GetRawCreationDate() < first.GetRawCreationDate() {
+			first = entity
+		}
+	}
+	return first
+}
+
+// authoredByUs определяет автора сущности: для сообщения - отправитель, для подписи - SignerBoxId,
+// для вложения в патче - ящик первой подписи под этим вложением (из события или из сообщения целиком).
+// Неподписанные внутренние вложения (резолюции, запросы согласования, маршруты) видны только
+// ящику, в котором созданы, и считаются нашими
+func (r *eventResolver) authoredByUs(ctx context.Context, entity *model.Entity, isMessage bool) (bool, error) {
+	boxID := r.dispatcher.boxID
+	if isMessage {
+		return r.event.GetMessage().GetFromBoxId() == boxID, nil
+	}
+	if entity.GetEntityType() == model.EntityType_TypeSignature {
+		return entity.GetSignerBoxId() == boxID, nil
+	}
+	if signature := firstSignature(r.entities, entity.GetEntityId()); signature != nil {
+		return signature.GetSignerBoxId() == boxID, nil
+	}
+	if isInternalAttachment(entity.GetAttachmentType()) {
+		return true, nil
+	}
+	if err := r.loadMessage(ctx); err != nil {
+		return false, err
+	}
+	if signature := firstSignature(r.message.Entities, entity.GetEntityId()); signature != nil {
+		return signature.GetSignerBoxId() == boxID, nil
+	}
+	return false, nil
+}
+
+// revocationRequestedByUs определяет, что предложение об аннулировании request отправлено текущим ящиком:
+// по InitiatorBoxId, а если он не заполнен - по первой подписи под предложением
+// (вторая подпись означает согласие контрагента)
+func (r *eventResolver) revocationRequestedByUs(ctx context.Context, request *model.Entity) (bool, error) {
+	if initiator := request.GetRevocationRequestInfo().GetInitiatorBoxId(); initiator != "" {
+		return initiator == r.dispatcher.boxID, nil
+	}
+	if err := r.loadMessage(ctx); err != nil {
+		return false, err
+	}
+	signature := firstSignature(r.message.Entities, request.GetEntityId())
+	return signature != nil && signature.GetSignerBoxId() == r.dispatcher.boxID, nil
+}
+
+// isInternalAttachment вложения внутреннего документооборота, которые не передаются контрагенту
+func isInternalAttachment(t model.AttachmentType) bool {
+	switch t {
+	case model.AttachmentType_Resolution,
+		model.AttachmentType_ResolutionRequest,
+		model.AttachmentType_ResolutionRequestDenialType,
+		model.AttachmentType_ResolutionRouteAssignmentType,
+		model.AttachmentType_ResolutionRouteRemovalType,
+		model.AttachmentType_CancellationType:
+		return true
+	}
+	return false
+}
@@ -5,4 +5,5 @@ go 1.19
 require (
 	github.com/google/uuid v1.3.0
 	google.golang.org/protobuf v1.28.1
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,194 @@
+package diadocсlient
+
+import (
+	"context"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"sync"
+)
+
+const (
+	innResolverChunkSize   = 100
+	innResolverConcurrency = 4
+)
+
+// InnKpp пара ИНН и КПП для поиска через GetOrganizationsByInnKpp
+type InnKpp struct {
+	INN string
+	KPP string
+}
+
+// InnResolution результат пакетного поиска организаций
+type InnResolution struct {
+	// Organizations найденные организации по ИНН (по одному ИНН может быть несколько организаций)
+	Organizations map[string][]*model.OrganizationWithCounteragentStatus
+	// NotFound ИНН, по которым в Диадоке нет организаций
+	NotFound []string
+}
+
+// InnKppResolution результат поиска организаций по парам ИНН и КПП
+type InnKppResolution struct {
+	Organizations map[InnKpp][]*model.Organization
+	NotFound      []InnKpp
+}
+
+// InnResolver ищет организации по большому списку ИНН: список разбивается на части, которые запрашиваются
+// одновременно. Частота запросов ограничивается лимитом клиента (см. DiadocClient.SetRateLimiter)
+type InnResolver struct {
+	client      DiadocClient
+	orgID       string
+	chunkSize   int
+	concurrency int
+}
+
+// NewInnResolver создает поиск от имени организации myOrgID (для статуса контрагента в результатах)
+func (c DiadocClient) NewInnResolver(myOrgID string) *InnResolver {
+	return &InnResolver{
+		client:      c,
+		orgID:       myOrgID,
+		chunkSize:   innResolverChunkSize,
+		concurrency: innResolverConcurrency,
+	}
+}
+
+// ChunkSize задает количество ИНН в одном запросе GetOrganizationsByInnList (по умолчанию 100)
+func (r *InnResolver) ChunkSize(n int) *InnResolver {
+	if n > 0 {
+		r.chunkSize = n
+	}
+	return r
+}
+
+// Concurrency задает количество одновременных запросов (по умолчанию 4)
+func (r *InnResolver) Concurrency(n int) *InnResolver {
+	if n > 0 {
+		r.concurrency = n
+	}
+	return r
+}
+
+// Resolve ищет организации по списку ИНН. Повторяющиеся ИНН запрашиваются один раз.
+// При ошибке любого запроса остальные отменяются и возвращается первая ошибка
+func (r *InnResolver) Resolve(ctx context.Context, INNs []string) (*InnResolution, error) {
+	unique := uniqueStrings(INNs)
+	var chunks [][]string
+	for from := 0; from < len(unique); from += r.chunkSize {
+		to := from + r.chunkSize
+		if to > len(unique) {
+			to = len(unique)
+		}
+		chunks = append(chunks, unique[from:to])
+	}
+
+	result := &InnResolution{Organizations: make(map[string][]*model.OrganizationWithCounteragentStatus)}
+	var mu sync.Mutex
+	err := r.run(ctx, len(chunks), func(ctx context.Context, i int) error {
+		response, err := r.client.GetOrganizationsByInnList(ctx, r.orgID, chunks[i])
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, organization := range response.Organizations {
+			inn := organization.GetOrganization().GetInn()
+			result.Organizations[inn] = append(result.Organizations[inn], organization)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, inn := range unique {
+		if _, ok := result.Organizations[inn]; !ok {
+			result.NotFound = append(result.NotFound, inn)
+		}
+	}
+	return result, nil
+}
+
+// ResolveInnKpp ищет организации по парам ИНН и КПП, выполняя запросы GetOrganizationsByInnKpp одновременно
+func (r *InnResolver) ResolveInnKpp(ctx context.Context, pairs []InnKpp, includeRelations bool) (*InnKppResolution, error) {
+	seen := make(map[InnKpp]bool, len(pairs))
+	var unique []InnKpp
+	for _, pair := range pairs {
+		if !seen[pair] {
+			seen[pair] = true
+			unique = append(unique, pair)
+		}
+	}
+
+	result := &InnKppResolution{Organizations: make(map[InnKpp][]*model.Organization)}
+	var mu sync.Mutex
+	err := r.run(ctx, len(unique), func(ctx context.Context, i int) error {
+		list, err := r.client.GetOrganizationsByInnKpp(ctx, unique[i].INN, unique[i].KPP, includeRelations)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if len(list.Organizations) > 0 {
+			result.Organizations[unique[i]] = list.Organizations
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, pair := range unique {
+		if _, ok := result.Organizations[pair]; !ok {
+			result.NotFound = append(result.NotFound, pair)
+		}
+	}
+	return result, nil
+}
+
+// run выполняет n задач не более чем в r.concurrency потоков и возвращает первую ошибку
+func (r *InnResolver) run(ctx context.Context, n int, task func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	tasks := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for w := 0; w < r.concurrency && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range tasks {
+				if err := task(ctx, i); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case tasks <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(tasks)
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var result []string
+	for _, value := range values {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
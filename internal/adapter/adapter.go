@@ -3,13 +3,17 @@ package adapter
 import (
 	"bytes"
 	"context"
-	"errors"
 	"fmt"
+	"github.com/DimaSSV/diadocclient/pkg/credentials"
 	"github.com/DimaSSV/diadocclient/pkg/model"
+	"github.com/DimaSSV/diadocclient/pkg/ratelimit"
+	"github.com/DimaSSV/diadocclient/pkg/tokenstore"
 	"google.golang.org/protobuf/proto"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 )
 
 const (
@@ -17,39 +21,159 @@ const (
 )
 
 type Adapter struct {
+	credentials credentials.Provider
+	// clientId и login из последних полученных учетных данных: по ним формируется заголовок
+	// Authorization и ключ в хранилище токенов
 	clientId string
 	login    string
-	password string
 	token    string
 	host     string
 	client   http.Client
+	limiter  *ratelimit.Limiter
+	store    tokenstore.Store
+	// maxAge возраст токена, после которого он обновляется до запроса (0 - только по ответу 401)
+	maxAge time.Duration
+	// mu защищает token, issuedAt, clientId, login, limiter и транспорт client при одновременных запросах
+	mu       sync.RWMutex
+	issuedAt time.Time
+	// loaded токен уже запрашивался из хранилища
+	loaded bool
+	// inflight выполняемый вход, который ожидают одновременные запросы
+	inflight *loginCall
+}
+
+// loginCall результат входа, общий для всех ожидающих его запросов
+type loginCall struct {
+	done chan struct{}
+	err  error
 }
 
 func New(login string, password string, clientID string, initialToken string) *Adapter {
+	adapter := NewWithCredentials(credentials.Static{Login: login, Password: password, ClientID: clientID}, initialToken)
+	adapter.clientId = clientID
+	adapter.login = login
+	return adapter
+}
+
+// NewWithCredentials создает адаптер, запрашивающий учетные данные у provider при каждом входе
+func NewWithCredentials(provider credentials.Provider, initialToken string) *Adapter {
 	adapter := Adapter{
-		clientId: clientID,
-		login:    login,
-		password: password,
-		token:    initialToken,
+		credentials: provider,
+		token:       initialToken,
+	}
+	if initialToken != "" {
+		// Время получения переданного токена неизвестно, отсчитываем от создания
+		adapter.issuedAt = time.Now()
 	}
 	return &adapter
 }
 
+// SetTokenStore задает хранилище, из которого берется токен перед первым входом и в которое
+// сохраняется каждый новый токен
+func (a *Adapter) SetTokenStore(store tokenstore.Store) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.store = store
+	a.loaded = false
+}
+
+// SetTokenMaxAge задает возраст токена, после которого он заранее обновляется
+func (a *Adapter) SetTokenMaxAge(maxAge time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.maxAge = maxAge
+}
+
+// TokenAge возвращает возраст текущего токена; false, если токен еще не получен
+func (a *Adapter) TokenAge() (time.Duration, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.token == "" {
+		return 0, false
+	}
+	return time.Since(a.issuedAt), true
+}
+
+// SetTransport задает транспорт HTTP-клиента. Один транспорт можно использовать в нескольких адаптерах,
+// чтобы они разделяли пул соединений
+func (a *Adapter) SetTransport(transport http.RoundTripper) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.client.Transport = transport
+}
+
+// httpClient возвращает копию HTTP-клиента, чтобы смена транспорта не затрагивала выполняемые запросы
+func (a *Adapter) httpClient() http.Client {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.client
+}
+
+// SetRateLimiter задает ограничение частоты запросов (nil - без ограничения)
+func (a *Adapter) SetRateLimiter(limiter *ratelimit.Limiter) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.limiter = limiter
+}
+
+func (a *Adapter) rateLimiter() *ratelimit.Limiter {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.limiter
+}
+
+// UpdateToken получает новый токен. Текущий токен остается действующим, пока новый не получен
 func (a *Adapter) UpdateToken(ctx context.Context) error {
-	a.token = ""
+	return a.refreshToken(ctx, a.getToken())
+}
+
+// refreshToken выполняет вход, если токен все еще равен stale. Одновременные вызовы не выполняют
+// отдельный вход, а ожидают уже начатого и получают его результат
+func (a *Adapter) refreshToken(ctx context.Context, stale string) error {
+	a.mu.Lock()
+	call := a.inflight
+	if call == nil {
+		if a.token != "" && a.token != stale {
+			// Токен уже обновлен другим запросом
+			a.mu.Unlock()
+			return nil
+		}
+		call = &loginCall{done: make(chan struct{})}
+		a.inflight = call
+		a.mu.Unlock()
+		call.err = a.authenticate(ctx)
+		a.mu.Lock()
+		a.inflight = nil
+		a.mu.Unlock()
+		close(call.done)
+		return call.err
+	}
+	a.mu.Unlock()
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// authenticate запрашивает учетные данные и выполняет вход с ними. Идентификатор клиента, логин и токен
+// заменяются только при успешном входе: при ошибке источника учетных данных или входа действует прежний токен
+func (a *Adapter) authenticate(ctx context.Context) error {
+	creds, err := a.credentials.Credentials(ctx)
+	if err != nil {
+		return err
+	}
 	params := make(map[string]string)
 	params["type"] = "password"
 	message, _ := proto.Marshal(&model.LoginPassword{
-		Login:    &a.login,
-		Password: &a.password,
+		Login:    &creds.Login,
+		Password: &creds.Password,
 	})
-	response, err := a.CallMethod(ctx, http.MethodPost, authEndpoint, &params, message)
+	response, err := a.send(ctx, http.MethodPost, authEndpoint, &params, message, authorization(creds.ClientID, ""))
 	if err != nil {
 		return err
 	}
-	if response.StatusCode != 200 {
-		return errors.New("authorization error")
-	}
 	body, err := io.ReadAll(response.Body)
 	defer func(Body io.ReadCloser) {
 		err = Body.Close()
@@ -57,25 +181,142 @@ func (a *Adapter) UpdateToken(ctx context.Context) error {
 			//log
 		}
 	}(response.Body)
-	a.token = string(body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("{%d} Не удалось выполнить вход:\n%s", response.StatusCode, string(body))
+	}
+	token := tokenstore.Token{Value: string(body), IssuedAt: time.Now()}
+	a.setSession(creds, token)
+	if store := a.tokenStore(); store != nil {
+		if err = store.Save(storeKey(creds.ClientID, creds.Login), token); err != nil {
+			//log
+		}
+	}
 	return nil
 }
 
+// ensureToken обеспечивает наличие действующего токена перед запросом: берет его из хранилища
+// при первом обращении, а если токена нет или он старше maxAge - выполняет вход
+func (a *Adapter) ensureToken(ctx context.Context) error {
+	if err := a.identify(ctx); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	if a.token == "" && a.store != nil && !a.loaded {
+		a.loaded = true
+		// Ошибка чтения хранилища не мешает работе: выполняется обычный вход
+		if token, ok, err := a.store.Load(storeKey(a.clientId, a.login)); err == nil && ok && token.Value != "" {
+			a.token = token.Value
+			a.issuedAt = token.IssuedAt
+		}
+	}
+	token := a.token
+	valid := token != "" && (a.maxAge <= 0 || time.Since(a.issuedAt) < a.maxAge)
+	a.mu.Unlock()
+	if valid {
+		return nil
+	}
+	return a.refreshToken(ctx, token)
+}
+
+func (a *Adapter) tokenStore() tokenstore.Store {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.store
+}
+
+// identify запрашивает учетные данные, если идентификатор клиента еще неизвестен
+// (адаптер создан с источником учетных данных и начальным токеном)
+func (a *Adapter) identify(ctx context.Context) error {
+	if a.getClientID() != "" {
+		return nil
+	}
+	creds, err := a.credentials.Credentials(ctx)
+	if err != nil {
+		return err
+	}
+	a.setIdentity(creds)
+	return nil
+}
+
+func (a *Adapter) setIdentity(creds credentials.Credentials) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clientId = creds.ClientID
+	a.login = creds.Login
+}
+
+func (a *Adapter) getClientID() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.clientId
+}
+
+// storeKey ключ учетной записи в хранилище токенов
+func storeKey(clientID string, login string) string {
+	return clientID + "/" + login
+}
+
+// setSession одновременно заменяет учетную запись и токен, чтобы запросы не получили токен одной учетной записи
+// с идентификатором клиента другой
+func (a *Adapter) setSession(creds credentials.Credentials, token tokenstore.Token) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clientId = creds.ClientID
+	a.login = creds.Login
+	a.token = token.Value
+	a.issuedAt = token.IssuedAt
+}
+
+func (a *Adapter) getToken() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.token
+}
+
+// CallMethod выполняет запрос. При ответе 401 токен обновляется и запрос повторяется один раз;
+// если и повторный запрос получает 401, ответ возвращается вызывающему
 func (a *Adapter) CallMethod(ctx context.Context, method string, resource string, params *map[string]string, data []byte) (*http.Response, error) {
-	var (
-		err      error
-		request  *http.Request
-		response *http.Response
-	)
-
-	if len(a.token) == 0 && strings.Compare(resource, authEndpoint) != 0 {
-		err = a.UpdateToken(ctx)
+	if strings.Compare(resource, authEndpoint) == 0 {
+		return a.send(ctx, method, resource, params, data, authorization(a.getClientID(), ""))
+	}
+	return a.call(ctx, method, resource, params, data, true)
+}
+
+func (a *Adapter) call(ctx context.Context, method string, resource string, params *map[string]string, data []byte, retry bool) (*http.Response, error) {
+	if err := a.ensureToken(ctx); err != nil {
+		return nil, err
+	}
+	// Идентификатор клиента и токен читаются вместе, так как вход может заменить их между запросами
+	a.mu.RLock()
+	clientID, token := a.clientId, a.token
+	a.mu.RUnlock()
+	response, err := a.send(ctx, method, resource, params, data, authorization(clientID, token))
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusUnauthorized && retry {
+		_ = response.Body.Close()
+		err = a.refreshToken(ctx, token)
 		if err != nil {
 			return nil, err
 		}
+		return a.call(ctx, method, resource, params, data, false)
 	}
+	return response, nil
+}
 
-	request, err = http.NewRequestWithContext(ctx, method, "https://diadoc-api.kontur.ru", bytes.NewBuffer(data))
+// send выполняет запрос с заголовком Authorization с учетом ограничения частоты запросов
+func (a *Adapter) send(ctx context.Context, method string, resource string, params *map[string]string, data []byte, auth string) (*http.Response, error) {
+	if limiter := a.rateLimiter(); limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, "https://diadoc-api.kontur.ru", bytes.NewBuffer(data))
 	if err != nil {
 		return nil, err
 	}
@@ -88,24 +329,16 @@ func (a *Adapter) CallMethod(ctx context.Context, method string, resource string
 		}
 		request.URL.RawQuery = q.Encode()
 	}
+	request.Header.Add("Authorization", auth)
 
-	if strings.Compare(resource, authEndpoint) == 0 {
-		request.Header.Add("Authorization", fmt.Sprintf("DiadocAuth ddauth_api_client_id=%s", a.clientId))
-	} else if len(a.token) > 0 {
-		request.Header.Add("Authorization",
-			fmt.Sprintf("DiadocAuth ddauth_api_client_id=%s,ddauth_token=%s", a.clientId, a.token))
-	} else {
-		// ??? вызвать получение токена?
-	}
-
-	response, err = a.client.Do(request)
+	client := a.httpClient()
+	return client.Do(request)
+}
 
-	if response.StatusCode == 401 && strings.Compare(resource, authEndpoint) != 0 {
-		err = a.UpdateToken(ctx)
-		if err != nil {
-			return nil, err
-		}
-		return a.CallMethod(ctx, method, resource, params, data)
+// authorization формирует заголовок Authorization; без токена - для запроса входа
+func authorization(clientID string, token string) string {
+	if token == "" {
+		return fmt.Sprintf("DiadocAuth ddauth_api_client_id=%s", clientID)
 	}
-	return response, nil
+	return fmt.Sprintf("DiadocAuth ddauth_api_client_id=%s,ddauth_token=%s", clientID, token)
 }
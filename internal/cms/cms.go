@@ -0,0 +1,160 @@
+package cms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+)
+
+var (
+	OIDData          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	OIDSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	OIDContentType   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	OIDMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	OIDSigningTime   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+
+	OIDSHA256          = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	OIDRSAEncryption   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	OIDECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+)
+
+type ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type SignedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo EncapsulatedContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []SignerInfo  `asn1:"set"`
+}
+
+type EncapsulatedContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type SignerInfo struct {
+	Version            int
+	SID                asn1.RawValue
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type IssuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type Attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+// SignDetached формирует открепленную подпись SignedData для data с подписанными атрибутами
+// contentType, signingTime и messageDigest. Поддерживаются ключи RSA и ECDSA, хэш SHA-256
+func SignDetached(data []byte, cert *x509.Certificate, key crypto.Signer, signingTime time.Time) ([]byte, error) {
+	var signatureAlgorithm asn1.ObjectIdentifier
+	switch key.Public().(type) {
+	case *rsa.PublicKey:
+		signatureAlgorithm = OIDRSAEncryption
+	case *ecdsa.PublicKey:
+		signatureAlgorithm = OIDECDSAWithSHA256
+	default:
+		return nil, errors.New("cms: неподдерживаемый тип ключа")
+	}
+	digest := crypto.SHA256.New()
+	digest.Write(data)
+
+	attrs, err := marshalAttributes(
+		[]asn1.ObjectIdentifier{OIDContentType, OIDSigningTime, OIDMessageDigest},
+		[]interface{}{OIDData, signingTime.UTC(), digest.Sum(nil)},
+	)
+	if err != nil {
+		return nil, err
+	}
+	// Подписывается DER-представление атрибутов с универсальным тегом SET
+	attrsDigest := crypto.SHA256.New()
+	attrsDigest.Write(append([]byte{0x31}, attrs[1:]...))
+	signature, err := key.Sign(rand.Reader, attrsDigest.Sum(nil), crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	signatureAlgorithmID := pkix.AlgorithmIdentifier{Algorithm: signatureAlgorithm}
+	if signatureAlgorithm.Equal(OIDRSAEncryption) {
+		signatureAlgorithmID.Parameters = asn1.NullRawValue
+	}
+	sid, err := asn1.Marshal(IssuerAndSerialNumber{
+		Issuer:       asn1.RawValue{FullBytes: cert.RawIssuer},
+		SerialNumber: cert.SerialNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+	digestAlgorithm := pkix.AlgorithmIdentifier{Algorithm: OIDSHA256}
+	signedData, err := asn1.Marshal(SignedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{digestAlgorithm},
+		EncapContentInfo: EncapsulatedContentInfo{EContentType: OIDData},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: cert.Raw},
+		SignerInfos: []SignerInfo{{
+			Version:            1,
+			SID:                asn1.RawValue{FullBytes: sid},
+			DigestAlgorithm:    digestAlgorithm,
+			SignedAttrs:        asn1.RawValue{FullBytes: attrs},
+			SignatureAlgorithm: signatureAlgorithmID,
+			Signature:          signature,
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(ContentInfo{
+		ContentType: OIDSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+}
+
+// marshalAttributes кодирует атрибуты как [0] IMPLICIT SET OF с сортировкой по DER
+func marshalAttributes(types []asn1.ObjectIdentifier, values []interface{}) ([]byte, error) {
+	encoded := make([][]byte, 0, len(types))
+	for i, oid := range types {
+		value, err := asn1.Marshal(values[i])
+		if err != nil {
+			return nil, err
+		}
+		attr, err := asn1.Marshal(Attribute{
+			Type:   oid,
+			Values: asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: value},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, attr)
+	}
+	sort.Slice(encoded, func(i, j int) bool {
+		return bytes.Compare(encoded[i], encoded[j]) < 0
+	})
+	return asn1.Marshal(asn1.RawValue{
+		Class:      asn1.ClassContextSpecific,
+		Tag:        0,
+		IsCompound: true,
+		Bytes:      bytes.Join(encoded, nil),
+	})
+}
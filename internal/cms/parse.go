@@ -0,0 +1,163 @@
+package cms
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+)
+
+// Parse разбирает ContentInfo с SignedData. Допускается BER-кодирование с неопределенной длиной,
+// которое формируют некоторые криптопровайдеры
+func Parse(data []byte) (*SignedData, error) {
+	der, err := berToDER(data)
+	if err != nil {
+		return nil, err
+	}
+	var info ContentInfo
+	rest, err := asn1.Unmarshal(der, &info)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("cms: лишние данные после ContentInfo")
+	}
+	if !info.ContentType.Equal(OIDSignedData) {
+		return nil, fmt.Errorf("cms: ожидался SignedData, получен %s", info.ContentType)
+	}
+	var signedData SignedData
+	if _, err = asn1.Unmarshal(info.Content.Bytes, &signedData); err != nil {
+		return nil, err
+	}
+	return &signedData, nil
+}
+
+// RawCertificates возвращает DER-представления сертификатов из поля certificates
+func (sd *SignedData) RawCertificates() ([][]byte, error) {
+	var result [][]byte
+	rest := sd.Certificates.Bytes
+	for len(rest) > 0 {
+		var cert asn1.RawValue
+		var err error
+		rest, err = asn1.Unmarshal(rest, &cert)
+		if err != nil {
+			return nil, err
+		}
+		// Пропускаем альтернативные формы сертификатов (attribute certificates и т.п.)
+		if cert.Class == asn1.ClassUniversal && cert.Tag == asn1.TagSequence {
+			result = append(result, cert.FullBytes)
+		}
+	}
+	return result, nil
+}
+
+// Attributes разбирает подписанные атрибуты
+func (si *SignerInfo) Attributes() ([]Attribute, error) {
+	var result []Attribute
+	rest := si.SignedAttrs.Bytes
+	for len(rest) > 0 {
+		var attr Attribute
+		var err error
+		rest, err = asn1.Unmarshal(rest, &attr)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, attr)
+	}
+	return result, nil
+}
+
+// berToDER переводит элементы с неопределенной длиной в определенную.
+// Остальные отличия BER от DER для подписей не встречаются и не обрабатываются
+func berToDER(data []byte) ([]byte, error) {
+	out, rest, err := convertElement(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(out, rest...), nil
+}
+
+func convertElement(data []byte) ([]byte, []byte, error) {
+	if len(data) < 2 {
+		return nil, nil, errors.New("cms: неожиданный конец данных")
+	}
+	offset := 1
+	if data[0]&0x1f == 0x1f {
+		for offset < len(data) && data[offset]&0x80 != 0 {
+			offset++
+		}
+		offset++
+	}
+	if offset >= len(data) {
+		return nil, nil, errors.New("cms: неожиданный конец данных")
+	}
+	header := data[:offset]
+	constructed := data[0]&0x20 != 0
+	lengthByte := data[offset]
+	offset++
+	if lengthByte == 0x80 {
+		if !constructed {
+			return nil, nil, errors.New("cms: неопределенная длина у примитивного элемента")
+		}
+		var content []byte
+		rest := data[offset:]
+		for {
+			if len(rest) < 2 {
+				return nil, nil, errors.New("cms: отсутствует маркер конца содержимого")
+			}
+			if rest[0] == 0 && rest[1] == 0 {
+				rest = rest[2:]
+				break
+			}
+			var element []byte
+			var err error
+			element, rest, err = convertElement(rest)
+			if err != nil {
+				return nil, nil, err
+			}
+			content = append(content, element...)
+		}
+		return append(append(append([]byte{}, header...), encodeLength(len(content))...), content...), rest, nil
+	}
+	length := int(lengthByte)
+	if lengthByte&0x80 != 0 {
+		n := int(lengthByte & 0x7f)
+		if n > 4 || offset+n > len(data) {
+			return nil, nil, errors.New("cms: некорректная длина элемента")
+		}
+		length = 0
+		for _, b := range data[offset : offset+n] {
+			length = length<<8 | int(b)
+		}
+		offset += n
+	}
+	if offset+length > len(data) || length < 0 {
+		return nil, nil, errors.New("cms: длина элемента превышает размер данных")
+	}
+	value := data[offset : offset+length]
+	rest := data[offset+length:]
+	if !constructed {
+		return data[:offset+length], rest, nil
+	}
+	var content []byte
+	for len(value) > 0 {
+		var element []byte
+		var err error
+		element, value, err = convertElement(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		content = append(content, element...)
+	}
+	return append(append(append([]byte{}, header...), encodeLength(len(content))...), content...), rest, nil
+}
+
+func encodeLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+	var b []byte
+	for l := length; l > 0; l >>= 8 {
+		b = append([]byte{byte(l)}, b...)
+	}
+	return append([]byte{0x80 | byte(len(b))}, b...)
+}
@@ -4,12 +4,11 @@ import (
 	"context"
 	"fmt"
 	"github.com/DimaSSV/diadocclient/internal/adapter"
+	"github.com/DimaSSV/diadocclient/pkg/async"
 	"github.com/DimaSSV/diadocclient/pkg/model"
 	"google.golang.org/protobuf/proto"
 	"io"
 	"net/http"
-	"strconv"
-	"time"
 )
 
 const (
@@ -67,12 +66,19 @@ func AcquireCounteragent(ctx context.Context, a *adapter.Adapter, myOrgID string
 	return &result, nil
 }
 
-func AcquireCounteragentResult(ctx context.Context, a *adapter.Adapter, taskId string) (*model.AcquireCounteragentResult, error) {
+// AcquireCounteragentResult ожидает завершения задачи AcquireCounteragent, опрашивая результат с паузами backoff
+func AcquireCounteragentResult(ctx context.Context, a *adapter.Adapter, taskId string, backoff async.Backoff) (*model.AcquireCounteragentResult, error) {
+	return async.Wait(ctx, backoff, func(ctx context.Context) (*model.AcquireCounteragentResult, async.Status, error) {
+		return acquireCounteragentResult(ctx, a, taskId)
+	})
+}
+
+func acquireCounteragentResult(ctx context.Context, a *adapter.Adapter, taskId string) (*model.AcquireCounteragentResult, async.Status, error) {
 	params := make(map[string]string)
 	params["taskId"] = taskId
 	response, err := a.CallMethod(ctx, http.MethodGet, acquireCounteragentResultEndpoint, &params, nil)
 	if err != nil {
-		return nil, err
+		return nil, async.Status{}, err
 	}
 	body, err := io.ReadAll(response.Body)
 	defer func(Body io.ReadCloser) {
@@ -82,36 +88,31 @@ func AcquireCounteragentResult(ctx context.Context, a *adapter.Adapter, taskId s
 		}
 	}(response.Body)
 	switch response.StatusCode {
-	case 204:
-		sleepTime, err := strconv.Atoi(response.Header.Get("Retry-After"))
-		if err != nil {
-			return nil, err
-		}
-		time.Sleep(time.Duration(sleepTime) * time.Second)
-		return AcquireCounteragentResult(ctx, a, taskId)
+	case http.StatusNoContent:
+		return nil, async.Status{InProgress: true, RetryAfter: async.ParseRetryAfter(response.Header)}, nil
 	case http.StatusBadRequest:
-		return nil, fmt.Errorf("{400} Данные в запросе имеют неверный формат или отсутствуют обязательные параметры:\n%s", string(body))
+		return nil, async.Status{}, fmt.Errorf("{400} Данные в запросе имеют неверный формат или отсутствуют обязательные параметры:\n%s", string(body))
 	case http.StatusUnauthorized:
-		return nil, fmt.Errorf("{401} В запросе отсутствует HTTP-заголовок Authorization или в этом заголовке содержатся некорректные авторизационные данные:\n%s", string(body))
+		return nil, async.Status{}, fmt.Errorf("{401} В запросе отсутствует HTTP-заголовок Authorization или в этом заголовке содержатся некорректные авторизационные данные:\n%s", string(body))
 	case http.StatusPaymentRequired:
-		return nil, fmt.Errorf("{402} У организации с указанным идентификатором boxId закончилась подписка на API:\n%s", string(body))
+		return nil, async.Status{}, fmt.Errorf("{402} У организации с указанным идентификатором boxId закончилась подписка на API:\n%s", string(body))
 	case http.StatusForbidden:
-		return nil, fmt.Errorf("{403} Доступ к ящику с предоставленным авторизационным токеном запрещен или у пользователя недостаточно прав для доступа ко всем документам организации:\n%s", string(body))
+		return nil, async.Status{}, fmt.Errorf("{403} Доступ к ящику с предоставленным авторизационным токеном запрещен или у пользователя недостаточно прав для доступа ко всем документам организации:\n%s", string(body))
 	case http.StatusNotFound:
-		return nil, fmt.Errorf("{404} В указанном ящике нет документов с указанными идентификаторами:\n%s", string(body))
+		return nil, async.Status{}, fmt.Errorf("{404} В указанном ящике нет документов с указанными идентификаторами:\n%s", string(body))
 	case http.StatusMethodNotAllowed:
-		return nil, fmt.Errorf("{405} Используется неподходящий HTTP-метод:\n%s", string(body))
+		return nil, async.Status{}, fmt.Errorf("{405} Используется неподходящий HTTP-метод:\n%s", string(body))
 	case http.StatusConflict:
-		return nil, fmt.Errorf("{409} Не удалось выполнить запрос на приглашение контрагента:\n%s", string(body))
+		return nil, async.Status{}, fmt.Errorf("{409} Не удалось выполнить запрос на приглашение контрагента:\n%s", string(body))
 	case http.StatusInternalServerError:
-		return nil, fmt.Errorf("{500} При обработке запроса возникла непредвиденная ошибка:\n%s", string(body))
+		return nil, async.Status{}, fmt.Errorf("{500} При обработке запроса возникла непредвиденная ошибка:\n%s", string(body))
 	}
 	result := model.AcquireCounteragentResult{}
 	err = proto.Unmarshal(body, &result)
 	if err != nil {
-		return nil, err
+		return nil, async.Status{}, err
 	}
-	return &result, nil
+	return &result, async.Status{}, nil
 }
 
 func BreakWithCounteragent(ctx context.Context, a *adapter.Adapter, myOrgID string, counteragentOrgID string, comment string) error {
@@ -2,12 +2,17 @@ package document
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/DimaSSV/diadocclient/internal/adapter"
+	"github.com/DimaSSV/diadocclient/pkg/async"
+	"github.com/DimaSSV/diadocclient/pkg/filter"
 	"github.com/DimaSSV/diadocclient/pkg/model"
+	"github.com/DimaSSV/diadocclient/pkg/ticks"
 	"github.com/google/uuid"
 	"google.golang.org/protobuf/proto"
 	"io"
+	"mime"
 	"net/http"
 	"strconv"
 	"time"
@@ -25,12 +30,22 @@ const (
 	getForwardedEntityContentEndpoint          = "/V2/GetForwardedEntityContent"
 	getForwardedDocumentsEndpoint              = "/V2/GetForwardedDocuments"
 	getGeneratedPrintFormEndpoint              = "/GetGeneratedPrintForm"
+	generatePrintFormEndpoint                  = "/GeneratePrintForm"
 	moveDocumentsEndpoint                      = "/MoveDocuments"
+	prepareDocumentsToSignEndpoint             = "/PrepareDocumentsToSign"
 	recycleDraftEndpoint                       = "/RecycleDraft"
 	restoreEndpoint                            = "/Restore"
 	shelfDownloadEndpoint                      = "/ShelfDownload"
 	shelfUploadEndpoint                        = "/ShelfUpload"
 	sendDraftEndpoint                          = "/SendDraft"
+	generateReceiptXmlEndpoint                 = "/V2/GenerateReceiptXml"
+	generateSignatureRejectionXmlEndpoint      = "/V2/GenerateSignatureRejectionXml"
+	generateRevocationRequestXmlEndpoint       = "/GenerateRevocationRequestXml"
+	generateRecipientTitleXmlEndpoint          = "/GenerateRecipientTitleXml"
+	generateCorrectionRequestXmlEndpoint       = "/GenerateInvoiceCorrectionRequestXml"
+	parseRevocationRequestXmlEndpoint          = "/ParseRevocationRequestXml"
+	parseSignatureRejectionXmlEndpoint         = "/ParseSignatureRejectionXml"
+	parseTitleXmlEndpoint                      = "/ParseTitleXml"
 	maxFilePartForShelf                        = 512 * 1024
 )
 
@@ -151,11 +166,7 @@ func ForwardDocument(ctx context.Context, a *adapter.Adapter, boxID string, requ
 //GenerateDocumentZip
 //GenerateForwardedDocumentPrintForm
 //GenerateForwardedDocumentProtocol
-//GenerateReceiptXml
-//GeneratePrintForm
 //GeneratePrintFormFromAttachment
-//GenerateRevocationRequestXml
-//GenerateSignatureRejectionXml
 
 func GetDocument(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, entityID string, injectEntityContent bool) (*model.Document, error) {
 	params := make(map[string]string)
@@ -202,32 +213,32 @@ func GetDocument(ctx context.Context, a *adapter.Adapter, boxID string, messageI
 
 type Filter struct {
 	BoxID                 string
-	FilterCategory        string
+	FilterCategory        filter.Category
 	CounteragentBoxID     string
 	FromDepartmentID      string
 	ToDepartmentID        string
 	DocumentNumber        string
-	TimestampFromTicks    time.Time
-	TimestampToTicks      time.Time
+	TimestampFromTicks    ticks.Ticks
+	TimestampToTicks      ticks.Ticks
 	FromDocumentDate      time.Time
 	ToDocumentDate        time.Time
 	DepartmentID          string
 	ExcludeSubdepartments bool
 	AfterIndexKey         string
-	SortDirection         string
+	SortDirection         filter.SortDirection
 	Count                 int
 }
 
 func NewFilter(boxID string, AfterIndexKey string) Filter {
 	return Filter{
 		BoxID:                 boxID,
-		FilterCategory:        "Any.Inbound",
+		FilterCategory:        filter.NewCategory(filter.AnyDocumentType, filter.Inbound),
 		CounteragentBoxID:     "",
 		FromDepartmentID:      "",
 		ToDepartmentID:        "",
 		DocumentNumber:        "",
-		TimestampFromTicks:    time.Time{},
-		TimestampToTicks:      time.Time{},
+		TimestampFromTicks:    0,
+		TimestampToTicks:      0,
 		FromDocumentDate:      time.Time{},
 		ToDocumentDate:        time.Time{},
 		DepartmentID:          "",
@@ -238,12 +249,35 @@ func NewFilter(boxID string, AfterIndexKey string) Filter {
 	}
 }
 
+// Validate проверяет параметры фильтра до отправки запроса
+func (f Filter) Validate() error {
+	if err := f.FilterCategory.Validate(); err != nil {
+		return err
+	}
+	if err := f.SortDirection.Validate(); err != nil {
+		return err
+	}
+	if !f.TimestampFromTicks.IsZero() && !f.TimestampToTicks.IsZero() && f.TimestampToTicks < f.TimestampFromTicks {
+		return errors.New("начало интервала времени позже его окончания")
+	}
+	if !f.FromDocumentDate.IsZero() && !f.ToDocumentDate.IsZero() && f.ToDocumentDate.Before(f.FromDocumentDate) {
+		return errors.New("начальная дата документа позже конечной")
+	}
+	if f.Count < 0 || f.Count > 100 {
+		return errors.New("количество документов должно быть от 1 до 100")
+	}
+	return nil
+}
+
 func GetDocuments(ctx context.Context, a *adapter.Adapter, filter Filter) (*model.DocumentList, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
 	return getDocuments(
 		ctx,
 		a,
 		filter.BoxID,
-		filter.FilterCategory,
+		string(filter.FilterCategory),
 		filter.CounteragentBoxID,
 		filter.DepartmentID,
 		filter.ToDepartmentID,
@@ -255,7 +289,7 @@ func GetDocuments(ctx context.Context, a *adapter.Adapter, filter Filter) (*mode
 		filter.DepartmentID,
 		filter.ExcludeSubdepartments,
 		filter.AfterIndexKey,
-		filter.SortDirection,
+		string(filter.SortDirection),
 		filter.Count,
 	)
 }
@@ -269,8 +303,8 @@ func getDocuments(
 	fromDepartmentID string,
 	toDepartmentID string,
 	documentNumber string,
-	timestampFromTicks time.Time,
-	timestampToTicks time.Time,
+	timestampFromTicks ticks.Ticks,
+	timestampToTicks ticks.Ticks,
 	fromDocumentDate time.Time,
 	toDocumentDate time.Time,
 	departmentID string,
@@ -295,10 +329,10 @@ func getDocuments(
 		params["documentNumber"] = documentNumber
 	}
 	if !timestampFromTicks.IsZero() {
-		params["timestampFromTicks"] = strconv.FormatInt(timestampFromTicks.UnixNano(), 10)
+		params["timestampFromTicks"] = timestampFromTicks.String()
 	}
 	if !timestampToTicks.IsZero() {
-		params["timestampToTicks"] = strconv.FormatInt(timestampToTicks.UnixNano(), 10)
+		params["timestampToTicks"] = timestampToTicks.String()
 	}
 	if !fromDocumentDate.IsZero() {
 		params["fromDocumentDate"] = fromDocumentDate.Format("02.01.2006")
@@ -591,6 +625,52 @@ func GetGeneratedPrintForm(ctx context.Context, a *adapter.Adapter, printFormID
 	return body, nil
 }
 
+// GeneratePrintForm формирует печатную форму документа (PDF). Пока Диадок формирует ее,
+// ответ содержит заголовок Retry-After, и запрос повторяется с ожиданием
+func GeneratePrintForm(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, documentID string, backoff async.Backoff) (*GeneratedFile, error) {
+	return async.Wait(ctx, backoff, func(ctx context.Context) (*GeneratedFile, async.Status, error) {
+		return generatePrintForm(ctx, a, boxID, messageID, documentID)
+	})
+}
+
+func generatePrintForm(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, documentID string) (*GeneratedFile, async.Status, error) {
+	params := make(map[string]string)
+	params["boxId"] = boxID
+	params["messageId"] = messageID
+	params["documentId"] = documentID
+	response, err := a.CallMethod(ctx, http.MethodGet, generatePrintFormEndpoint, &params, nil)
+	if err != nil {
+		return nil, async.Status{}, err
+	}
+	body, err := io.ReadAll(response.Body)
+	defer func(Body io.ReadCloser) {
+		err = Body.Close()
+		if err != nil {
+			//log
+		}
+	}(response.Body)
+	switch response.StatusCode {
+	case http.StatusBadRequest:
+		return nil, async.Status{}, fmt.Errorf("{400} Данные в запросе имеют неверный формат или отсутствуют обязательные параметры:\n%s", string(body))
+	case http.StatusUnauthorized:
+		return nil, async.Status{}, fmt.Errorf("{401} В запросе отсутствует HTTP-заголовок Authorization или в этом заголовке содержатся некорректные авторизационные данные:\n%s", string(body))
+	case http.StatusPaymentRequired:
+		return nil, async.Status{}, fmt.Errorf("{402} У организации с указанным идентификатором boxId закончилась подписка на API:\n%s", string(body))
+	case http.StatusForbidden:
+		return nil, async.Status{}, fmt.Errorf("{403} Доступ к ящику с предоставленным авторизационным токеном запрещен:\n%s", string(body))
+	case http.StatusNotFound:
+		return nil, async.Status{}, fmt.Errorf("{404} В указанном ящике нет документа с указанными идентификаторами:\n%s", string(body))
+	case http.StatusMethodNotAllowed:
+		return nil, async.Status{}, fmt.Errorf("{405} Используется неподходящий HTTP-метод:\n%s", string(body))
+	case http.StatusInternalServerError:
+		return nil, async.Status{}, fmt.Errorf("{500} при обработке запроса возникла непредвиденная ошибка:\n%s", string(body))
+	}
+	if _, ok := response.Header["Retry-After"]; ok {
+		return nil, async.Status{InProgress: true, RetryAfter: async.ParseRetryAfter(response.Header)}, nil
+	}
+	return newGeneratedFile(response, body), async.Status{}, nil
+}
+
 func MoveDocuments(ctx context.Context, a *adapter.Adapter, operation *model.DocumentsMoveOperation) error {
 	message, _ := proto.Marshal(operation)
 	response, err := a.CallMethod(ctx, http.MethodPost, moveDocumentsEndpoint, nil, message)
@@ -621,11 +701,42 @@ func MoveDocuments(ctx context.Context, a *adapter.Adapter, operation *model.Doc
 	return nil
 }
 
-//ToDO: Ещё методы
-//ParseTitleXml
-//ParseRevocationRequestXml
-//ParseSignatureRejectionXml
-//PrepareDocumentsToSign
+func PrepareDocumentsToSign(ctx context.Context, a *adapter.Adapter, request *model.PrepareDocumentsToSignRequest) (*model.PrepareDocumentsToSignResponse, error) {
+	message, _ := proto.Marshal(request)
+	response, err := a.CallMethod(ctx, http.MethodPost, prepareDocumentsToSignEndpoint, nil, message)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(response.Body)
+	defer func(Body io.ReadCloser) {
+		err = Body.Close()
+		if err != nil {
+			//log
+		}
+	}(response.Body)
+	switch response.StatusCode {
+	case http.StatusBadRequest:
+		return nil, fmt.Errorf("{400} Данные в запросе имеют неверный формат или отсутствуют обязательные параметры:\n%s", string(body))
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("{401} В запросе отсутствует HTTP-заголовок Authorization или в этом заголовке содержатся некорректные авторизационные данные:\n%s", string(body))
+	case http.StatusPaymentRequired:
+		return nil, fmt.Errorf("{402} У организации с указанным идентификатором boxId закончилась подписка на API:\n%s", string(body))
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("{403} Доступ к ящику с предоставленным авторизационным токеном запрещен или у пользователя нет доступа к каким-то документам из запроса:\n%s", string(body))
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("{404} В указанном ящике нет документов или черновиков с указанными идентификаторами:\n%s", string(body))
+	case http.StatusMethodNotAllowed:
+		return nil, fmt.Errorf("{405} Используется неподходящий HTTP-метод:\n%s", string(body))
+	case http.StatusInternalServerError:
+		return nil, fmt.Errorf("{500} при обработке запроса возникла непредвиденная ошибка:\n%s", string(body))
+	}
+	result := model.PrepareDocumentsToSignResponse{}
+	err = proto.Unmarshal(body, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
 
 func RecycleDraft(ctx context.Context, a *adapter.Adapter, boxID string, draftID string) error {
 	params := make(map[string]string)
@@ -833,3 +944,305 @@ func SendDraft(ctx context.Context, a *adapter.Adapter, operationID string, send
 	}
 	return &result, nil
 }
+
+// GeneratedFile содержимое служебного документа, сформированного Диадоком,
+// и имя файла из заголовка Content-Disposition
+type GeneratedFile struct {
+	FileName string
+	Content  []byte
+}
+
+func GenerateReceiptXml(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, attachmentID string, signer *model.Signer) (*GeneratedFile, error) {
+	params := make(map[string]string)
+	params["boxId"] = boxID
+	params["messageId"] = messageID
+	params["attachmentId"] = attachmentID
+	message, _ := proto.Marshal(signer)
+	response, err := a.CallMethod(ctx, http.MethodPost, generateReceiptXmlEndpoint, &params, message)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(response.Body)
+	defer func(Body io.ReadCloser) {
+		err = Body.Close()
+		if err != nil {
+			//log
+		}
+	}(response.Body)
+	switch response.StatusCode {
+	case http.StatusBadRequest:
+		return nil, fmt.Errorf("{400} Данные в запросе имеют неверный формат или отсутствуют обязательные параметры:\n%s", string(body))
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("{401} В запросе отсутствует HTTP-заголовок Authorization или в этом заголовке содержатся некорректные авторизационные данные:\n%s", string(body))
+	case http.StatusPaymentRequired:
+		return nil, fmt.Errorf("{402} У организации с указанным идентификатором boxId закончилась подписка на API:\n%s", string(body))
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("{403} Доступ к ящику с предоставленным авторизационным токеном запрещен:\n%s", string(body))
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("{404} В указанном ящике нет сообщения с идентификатором messageId или в сообщении нет сущности с идентификатором attachmentId:\n%s", string(body))
+	case http.StatusMethodNotAllowed:
+		return nil, fmt.Errorf("{405} Используется неподходящий HTTP-метод:\n%s", string(body))
+	case http.StatusConflict:
+		return nil, fmt.Errorf("{409} Для указанной сущности извещение о получении не требуется или уже сформировано:\n%s", string(body))
+	case http.StatusInternalServerError:
+		return nil, fmt.Errorf("{500} При обработке запроса возникла непредвиденная ошибка:\n%s", string(body))
+	}
+	return newGeneratedFile(response, body), nil
+}
+
+func GenerateSignatureRejectionXml(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, attachmentID string, info *model.SignatureRejectionInfo) (*GeneratedFile, error) {
+	params := make(map[string]string)
+	params["boxId"] = boxID
+	params["messageId"] = messageID
+	params["attachmentId"] = attachmentID
+	message, _ := proto.Marshal(info)
+	response, err := a.CallMethod(ctx, http.MethodPost, generateSignatureRejectionXmlEndpoint, &params, message)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(response.Body)
+	defer func(Body io.ReadCloser) {
+		err = Body.Close()
+		if err != nil {
+			//log
+		}
+	}(response.Body)
+	switch response.StatusCode {
+	case http.StatusBadRequest:
+		return nil, fmt.Errorf("{400} Данные в запросе имеют неверный формат или отсутствуют обязательные параметры:\n%s", string(body))
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("{401} В запросе отсутствует HTTP-заголовок Authorization или в этом заголовке содержатся некорректные авторизационные данные:\n%s", string(body))
+	case http.StatusPaymentRequired:
+		return nil, fmt.Errorf("{402} У организации с указанным идентификатором boxId закончилась подписка на API:\n%s", string(body))
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("{403} Доступ к ящику с предоставленным авторизационным токеном запрещен:\n%s", string(body))
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("{404} В указанном ящике нет сообщения с идентификатором messageId или в сообщении нет документа с идентификатором attachmentId:\n%s", string(body))
+	case http.StatusMethodNotAllowed:
+		return nil, fmt.Errorf("{405} Используется неподходящий HTTP-метод:\n%s", string(body))
+	case http.StatusConflict:
+		return nil, fmt.Errorf("{409} Для указанного документа отказ в подписи недопустим:\n%s", string(body))
+	case http.StatusInternalServerError:
+		return nil, fmt.Errorf("{500} При обработке запроса возникла непредвиденная ошибка:\n%s", string(body))
+	}
+	return newGeneratedFile(response, body), nil
+}
+
+func GenerateRevocationRequestXml(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, attachmentID string, info *model.RevocationRequestInfo) (*GeneratedFile, error) {
+	params := make(map[string]string)
+	params["boxId"] = boxID
+	params["messageId"] = messageID
+	params["attachmentId"] = attachmentID
+	message, _ := proto.Marshal(info)
+	response, err := a.CallMethod(ctx, http.MethodPost, generateRevocationRequestXmlEndpoint, &params, message)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(response.Body)
+	defer func(Body io.ReadCloser) {
+		err = Body.Close()
+		if err != nil {
+			//log
+		}
+	}(response.Body)
+	switch response.StatusCode {
+	case http.StatusBadRequest:
+		return nil, fmt.Errorf("{400} Данные в запросе имеют неверный формат или отсутствуют обязательные параметры:\n%s", string(body))
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("{401} В запросе отсутствует HTTP-заголовок Authorization или в этом заголовке содержатся некорректные авторизационные данные:\n%s", string(body))
+	case http.StatusPaymentRequired:
+		return nil, fmt.Errorf("{402} У организации с указанным идентификатором boxId закончилась подписка на API:\n%s", string(body))
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("{403} Доступ к ящику с предоставленным авторизационным токеном запрещен:\n%s", string(body))
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("{404} В указанном ящике нет сообщения с идентификатором messageId или в сообщении нет документа с идентификатором attachmentId:\n%s", string(body))
+	case http.StatusMethodNotAllowed:
+		return nil, fmt.Errorf("{405} Используется неподходящий HTTP-метод:\n%s", string(body))
+	case http.StatusConflict:
+		return nil, fmt.Errorf("{409} Для указанного документа аннулирование недопустимо:\n%s", string(body))
+	case http.StatusInternalServerError:
+		return nil, fmt.Errorf("{500} При обработке запроса возникла непредвиденная ошибка:\n%s", string(body))
+	}
+	return newGeneratedFile(response, body), nil
+}
+
+func GenerateRecipientTitleXml(ctx context.Context, a *adapter.Adapter, boxID string, senderTitleMessageID string, senderTitleAttachmentID string, documentVersion string, userContractData []byte) (*GeneratedFile, error) {
+	params := make(map[string]string)
+	params["boxId"] = boxID
+	params["senderTitleMessageId"] = senderTitleMessageID
+	params["senderTitleAttachmentId"] = senderTitleAttachmentID
+	if documentVersion != "" {
+		params["documentVersion"] = documentVersion
+	}
+	response, err := a.CallMethod(ctx, http.MethodPost, generateRecipientTitleXmlEndpoint, &params, userContractData)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(response.Body)
+	defer func(Body io.ReadCloser) {
+		err = Body.Close()
+		if err != nil {
+			//log
+		}
+	}(response.Body)
+	switch response.StatusCode {
+	case http.StatusBadRequest:
+		return nil, fmt.Errorf("{400} Данные в запросе имеют неверный формат, отсутствуют обязательные параметры или XML не соответствует контракту титула:\n%s", string(body))
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("{401} В запросе отсутствует HTTP-заголовок Authorization или в этом заголовке содержатся некорректные авторизационные данные:\n%s", string(body))
+	case http.StatusPaymentRequired:
+		return nil, fmt.Errorf("{402} У организации с указанным идентификатором boxId закончилась подписка на API:\n%s", string(body))
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("{403} Доступ к ящику с предоставленным авторизационным токеном запрещен:\n%s", string(body))
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("{404} В указанном ящике нет сообщения с идентификатором senderTitleMessageId или в сообщении нет титула отправителя с идентификатором senderTitleAttachmentId:\n%s", string(body))
+	case http.StatusMethodNotAllowed:
+		return nil, fmt.Errorf("{405} Используется неподходящий HTTP-метод:\n%s", string(body))
+	case http.StatusConflict:
+		return nil, fmt.Errorf("{409} Для указанного документа титул получателя не требуется или уже сформирован:\n%s", string(body))
+	case http.StatusInternalServerError:
+		return nil, fmt.Errorf("{500} При обработке запроса возникла непредвиденная ошибка:\n%s", string(body))
+	}
+	return newGeneratedFile(response, body), nil
+}
+
+func GenerateCorrectionRequestXml(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, attachmentID string, info *model.InvoiceCorrectionRequestInfo) (*GeneratedFile, error) {
+	params := make(map[string]string)
+	params["boxId"] = boxID
+	params["messageId"] = messageID
+	params["attachmentId"] = attachmentID
+	message, _ := proto.Marshal(info)
+	response, err := a.CallMethod(ctx, http.MethodPost, generateCorrectionRequestXmlEndpoint, &params, message)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(response.Body)
+	defer func(Body io.ReadCloser) {
+		err = Body.Close()
+		if err != nil {
+			//log
+		}
+	}(response.Body)
+	switch response.StatusCode {
+	case http.StatusBadRequest:
+		return nil, fmt.Errorf("{400} Данные в запросе имеют неверный формат или отсутствуют обязательные параметры:\n%s", string(body))
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("{401} В запросе отсутствует HTTP-заголовок Authorization или в этом заголовке содержатся некорректные авторизационные данные:\n%s", string(body))
+	case http.StatusPaymentRequired:
+		return nil, fmt.Errorf("{402} У организации с указанным идентификатором boxId закончилась подписка на API:\n%s", string(body))
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("{403} Доступ к ящику с предоставленным авторизационным токеном запрещен:\n%s", string(body))
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("{404} В указанном ящике нет сообщения с идентификатором messageId или в сообщении нет документа с идентификатором attachmentId:\n%s", string(body))
+	case http.StatusMethodNotAllowed:
+		return nil, fmt.Errorf("{405} Используется неподходящий HTTP-метод:\n%s", string(body))
+	case http.StatusConflict:
+		return nil, fmt.Errorf("{409} Для указанного документа уведомление об уточнении недопустимо:\n%s", string(body))
+	case http.StatusInternalServerError:
+		return nil, fmt.Errorf("{500} При обработке запроса возникла непредвиденная ошибка:\n%s", string(body))
+	}
+	return newGeneratedFile(response, body), nil
+}
+
+func ParseRevocationRequestXml(ctx context.Context, a *adapter.Adapter, xmlContent []byte) (*model.RevocationRequestInfo, error) {
+	response, err := a.CallMethod(ctx, http.MethodPost, parseRevocationRequestXmlEndpoint, nil, xmlContent)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(response.Body)
+	defer func(Body io.ReadCloser) {
+		err = Body.Close()
+		if err != nil {
+			//log
+		}
+	}(response.Body)
+	switch response.StatusCode {
+	case http.StatusBadRequest:
+		return nil, fmt.Errorf("{400} Переданный XML не является соглашением об аннулировании:\n%s", string(body))
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("{401} В запросе отсутствует HTTP-заголовок Authorization или в этом заголовке содержатся некорректные авторизационные данные:\n%s", string(body))
+	case http.StatusMethodNotAllowed:
+		return nil, fmt.Errorf("{405} Используется неподходящий HTTP-метод:\n%s", string(body))
+	case http.StatusInternalServerError:
+		return nil, fmt.Errorf("{500} При обработке запроса возникла непредвиденная ошибка:\n%s", string(body))
+	}
+	result := model.RevocationRequestInfo{}
+	err = proto.Unmarshal(body, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func ParseSignatureRejectionXml(ctx context.Context, a *adapter.Adapter, xmlContent []byte) (*model.SignatureRejectionInfo, error) {
+	response, err := a.CallMethod(ctx, http.MethodPost, parseSignatureRejectionXmlEndpoint, nil, xmlContent)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(response.Body)
+	defer func(Body io.ReadCloser) {
+		err = Body.Close()
+		if err != nil {
+			//log
+		}
+	}(response.Body)
+	switch response.StatusCode {
+	case http.StatusBadRequest:
+		return nil, fmt.Errorf("{400} Переданный XML не является уведомлением об отказе в подписи:\n%s", string(body))
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("{401} В запросе отсутствует HTTP-заголовок Authorization или в этом заголовке содержатся некорректные авторизационные данные:\n%s", string(body))
+	case http.StatusMethodNotAllowed:
+		return nil, fmt.Errorf("{405} Используется неподходящий HTTP-метод:\n%s", string(body))
+	case http.StatusInternalServerError:
+		return nil, fmt.Errorf("{500} При обработке запроса возникла непредвиденная ошибка:\n%s", string(body))
+	}
+	result := model.SignatureRejectionInfo{}
+	err = proto.Unmarshal(body, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func ParseTitleXml(ctx context.Context, a *adapter.Adapter, boxID string, documentTypeNamedID string, documentFunction string, documentVersion string, titleIndex int, xmlContent []byte) ([]byte, error) {
+	params := make(map[string]string)
+	params["boxId"] = boxID
+	params["documentTypeNamedId"] = documentTypeNamedID
+	params["documentFunction"] = documentFunction
+	params["documentVersion"] = documentVersion
+	params["titleIndex"] = strconv.Itoa(titleIndex)
+	response, err := a.CallMethod(ctx, http.MethodPost, parseTitleXmlEndpoint, &params, xmlContent)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(response.Body)
+	defer func(Body io.ReadCloser) {
+		err = Body.Close()
+		if err != nil {
+			//log
+		}
+	}(response.Body)
+	switch response.StatusCode {
+	case http.StatusBadRequest:
+		return nil, fmt.Errorf("{400} Данные в запросе имеют неверный формат, отсутствуют обязательные параметры или XML не соответствует указанному типу документа:\n%s", string(body))
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("{401} В запросе отсутствует HTTP-заголовок Authorization или в этом заголовке содержатся некорректные авторизационные данные:\n%s", string(body))
+	case http.StatusPaymentRequired:
+		return nil, fmt.Errorf("{402} У организации с указанным идентификатором boxId закончилась подписка на API:\n%s", string(body))
+	case http.StatusForbidden:
+		return nil, fmt.Errorf("{403} Доступ к ящику с предоставленным авторизационным токеном запрещен:\n%s", string(body))
+	case http.StatusMethodNotAllowed:
+		return nil, fmt.Errorf("{405} Используется неподходящий HTTP-метод:\n%s", string(body))
+	case http.StatusInternalServerError:
+		return nil, fmt.Errorf("{500} При обработке запроса возникла непредвиденная ошибка:\n%s", string(body))
+	}
+	return body, nil
+}
+
+func newGeneratedFile(response *http.Response, body []byte) *GeneratedFile {
+	result := GeneratedFile{Content: body}
+	if _, params, err := mime.ParseMediaType(response.Header.Get("Content-Disposition")); err == nil {
+		result.FileName = params["filename"]
+	}
+	return &result
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/DimaSSV/diadocclient/internal/adapter"
+	"github.com/DimaSSV/diadocclient/pkg/filter"
 	"github.com/DimaSSV/diadocclient/pkg/model"
 	"google.golang.org/protobuf/proto"
 	"io"
@@ -57,42 +58,31 @@ func GetEvent(ctx context.Context, a *adapter.Adapter, boxID string, eventID str
 	return &result, nil
 }
 
-func GetNewEvents(
-	ctx context.Context,
-	a *adapter.Adapter,
-	boxID string,
-	afterIndexKey string,
-	departmentID string,
-	messageTypes []string,
-	typeNamedIDs []string,
-	documentDirections []string,
-	timestampFromTicks int64,
-	timestampToTicks int64,
-	counteragentBoxID string,
-	orderBy string,
-	limit int,
-) (*model.BoxEventList, error) {
+func GetNewEvents(ctx context.Context, a *adapter.Adapter, boxID string, options filter.Events) (*model.BoxEventList, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
 	params := make(map[string]string)
 	params["boxId"] = boxID
-	if afterIndexKey != "" {
-		params["afterIndexKey"] = afterIndexKey
+	if options.AfterIndexKey != "" {
+		params["afterIndexKey"] = options.AfterIndexKey
 	}
-	if departmentID != "" {
-		params["departmentId"] = departmentID
+	if options.DepartmentID != "" {
+		params["departmentId"] = options.DepartmentID
 	}
-	if messageTypes != nil {
+	if options.MessageTypes != nil {
 		var buf strings.Builder
-		for _, messageType := range messageTypes {
+		for _, messageType := range options.MessageTypes {
 			if buf.Len() > 0 {
 				buf.WriteByte(',')
 			}
-			buf.WriteString(messageType)
+			buf.WriteString(string(messageType))
 		}
 		params["messageType"] = buf.String()
 	}
-	if typeNamedIDs != nil {
+	if options.TypeNamedIDs != nil {
 		var buf strings.Builder
-		for _, typeNameDoc := range typeNamedIDs {
+		for _, typeNameDoc := range options.TypeNamedIDs {
 			if buf.Len() > 0 {
 				buf.WriteByte(',')
 			}
@@ -100,30 +90,30 @@ func GetNewEvents(
 		}
 		params["typeNamedId"] = buf.String()
 	}
-	if documentDirections != nil {
+	if options.DocumentDirections != nil {
 		var buf strings.Builder
-		for _, direction := range documentDirections {
+		for _, direction := range options.DocumentDirections {
 			if buf.Len() > 0 {
 				buf.WriteByte(',')
 			}
-			buf.WriteString(direction)
+			buf.WriteString(string(direction))
 		}
 		params["documentDirection"] = buf.String()
 	}
-	if timestampFromTicks != 0 {
-		params["timestampFromTicks"] = strconv.FormatInt(timestampFromTicks, 10)
+	if !options.TimestampFrom.IsZero() {
+		params["timestampFromTicks"] = options.TimestampFrom.String()
 	}
-	if timestampToTicks != 0 {
-		params["timestampToTicks"] = strconv.FormatInt(timestampToTicks, 10)
+	if !options.TimestampTo.IsZero() {
+		params["timestampToTicks"] = options.TimestampTo.String()
 	}
-	if counteragentBoxID != "" {
-		params["counteragentBoxId"] = counteragentBoxID
+	if options.CounteragentBoxID != "" {
+		params["counteragentBoxId"] = options.CounteragentBoxID
 	}
-	if orderBy != "" {
-		params["orderBy"] = orderBy
+	if options.OrderBy != "" {
+		params["orderBy"] = string(options.OrderBy)
 	}
-	if limit != 0 {
-		params["limit"] = strconv.Itoa(limit)
+	if options.Limit != 0 {
+		params["limit"] = strconv.Itoa(options.Limit)
 	}
 	response, err := a.CallMethod(ctx, http.MethodGet, getNewEventsEndpoint, &params, nil)
 	if err != nil {
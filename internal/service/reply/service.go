@@ -0,0 +1,198 @@
+package reply
+
+import (
+	"context"
+	"errors"
+	"github.com/DimaSSV/diadocclient/internal/adapter"
+	"github.com/DimaSSV/diadocclient/internal/service/document"
+	"github.com/DimaSSV/diadocclient/internal/service/message"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"github.com/DimaSSV/diadocclient/pkg/signer"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+// AcceptDocument подписывает входящий документ. Для формализованных документов
+// в recipientTitle передается XML по контракту титула получателя (UserContract),
+// из которого формируется и подписывается титул; для остальных recipientTitle = nil
+// и подписывается содержимое самого документа
+func AcceptDocument(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, documentID string, recipientTitle []byte, s signer.Signer) (*model.MessagePatch, error) {
+	patch := newPatch(boxID, messageID)
+	if recipientTitle != nil {
+		title, err := document.GenerateRecipientTitleXml(ctx, a, boxID, messageID, documentID, "", recipientTitle)
+		if err != nil {
+			return nil, err
+		}
+		content, err := signContent(ctx, s, title.Content)
+		if err != nil {
+			return nil, err
+		}
+		patch.RecipientTitles = append(patch.RecipientTitles, &model.RecipientTitleAttachment{
+			ParentEntityId: proto.String(documentID),
+			SignedContent:  content,
+			NeedReceipt:    proto.Bool(false),
+		})
+	} else {
+		data, err := message.GetEntityContent(ctx, a, boxID, messageID, documentID)
+		if err != nil {
+			return nil, err
+		}
+		signature, err := s.Sign(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		patch.Signatures = append(patch.Signatures, &model.DocumentSignature{
+			ParentEntityId: proto.String(documentID),
+			Signature:      signature,
+		})
+	}
+	return message.PostMessagePatch(ctx, a, uuid.NewString(), patch)
+}
+
+// RejectDocument отказывает в подписи входящего документа с указанием причины
+func RejectDocument(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, documentID string, reason string, s signer.Signer) (*model.MessagePatch, error) {
+	info, err := signerInfo(s)
+	if err != nil {
+		return nil, err
+	}
+	rejection, err := document.GenerateSignatureRejectionXml(ctx, a, boxID, messageID, documentID, &model.SignatureRejectionInfo{
+		ErrorMessage: proto.String(reason),
+		Signer:       info,
+	})
+	if err != nil {
+		return nil, err
+	}
+	content, err := signContent(ctx, s, rejection.Content)
+	if err != nil {
+		return nil, err
+	}
+	patch := newPatch(boxID, messageID)
+	patch.XmlSignatureRejections = append(patch.XmlSignatureRejections, &model.XmlSignatureRejectionAttachment{
+		ParentEntityId: proto.String(documentID),
+		SignedContent:  content,
+	})
+	return message.PostMessagePatch(ctx, a, uuid.NewString(), patch)
+}
+
+// RequestRevocation отправляет контрагенту предложение об аннулировании документа
+func RequestRevocation(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, documentID string, reason string, s signer.Signer) (*model.MessagePatch, error) {
+	info, err := signerInfo(s)
+	if err != nil {
+		return nil, err
+	}
+	request, err := document.GenerateRevocationRequestXml(ctx, a, boxID, messageID, documentID, &model.RevocationRequestInfo{
+		Comment: proto.String(reason),
+		Signer:  info,
+	})
+	if err != nil {
+		return nil, err
+	}
+	content, err := signContent(ctx, s, request.Content)
+	if err != nil {
+		return nil, err
+	}
+	patch := newPatch(boxID, messageID)
+	patch.RevocationRequests = append(patch.RevocationRequests, &model.RevocationRequestAttachment{
+		ParentEntityId: proto.String(documentID),
+		SignedContent:  content,
+	})
+	return message.PostMessagePatch(ctx, a, uuid.NewString(), patch)
+}
+
+// AcceptRevocation подписывает полученное предложение об аннулировании
+func AcceptRevocation(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, revocationRequestID string, s signer.Signer) (*model.MessagePatch, error) {
+	data, err := message.GetEntityContent(ctx, a, boxID, messageID, revocationRequestID)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := s.Sign(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	patch := newPatch(boxID, messageID)
+	patch.Signatures = append(patch.Signatures, &model.DocumentSignature{
+		ParentEntityId: proto.String(revocationRequestID),
+		Signature:      signature,
+	})
+	return message.PostMessagePatch(ctx, a, uuid.NewString(), patch)
+}
+
+// RejectRevocation отказывает в подписи полученного предложения об аннулировании
+func RejectRevocation(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, revocationRequestID string, reason string, s signer.Signer) (*model.MessagePatch, error) {
+	return RejectDocument(ctx, a, boxID, messageID, revocationRequestID, reason, s)
+}
+
+// RequestCorrection отправляет уведомление об уточнении документа
+func RequestCorrection(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, documentID string, reason string, s signer.Signer) (*model.MessagePatch, error) {
+	info, err := signerInfo(s)
+	if err != nil {
+		return nil, err
+	}
+	request, err := document.GenerateCorrectionRequestXml(ctx, a, boxID, messageID, documentID, &model.InvoiceCorrectionRequestInfo{
+		Signer:       info,
+		ErrorMessage: proto.String(reason),
+	})
+	if err != nil {
+		return nil, err
+	}
+	content, err := signContent(ctx, s, request.Content)
+	if err != nil {
+		return nil, err
+	}
+	patch := newPatch(boxID, messageID)
+	patch.CorrectionRequests = append(patch.CorrectionRequests, &model.CorrectionRequestAttachment{
+		ParentEntityId: proto.String(documentID),
+		SignedContent:  content,
+	})
+	return message.PostMessagePatch(ctx, a, uuid.NewString(), patch)
+}
+
+// SendReceipt формирует, подписывает и отправляет извещение о получении для сущности attachmentID
+func SendReceipt(ctx context.Context, a *adapter.Adapter, boxID string, messageID string, attachmentID string, s signer.Signer) (*model.MessagePatch, error) {
+	info, err := signerInfo(s)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := document.GenerateReceiptXml(ctx, a, boxID, messageID, attachmentID, info)
+	if err != nil {
+		return nil, err
+	}
+	content, err := signContent(ctx, s, receipt.Content)
+	if err != nil {
+		return nil, err
+	}
+	patch := newPatch(boxID, messageID)
+	patch.Receipts = append(patch.Receipts, &model.ReceiptAttachment{
+		ParentEntityId: proto.String(attachmentID),
+		SignedContent:  content,
+	})
+	return message.PostMessagePatch(ctx, a, uuid.NewString(), patch)
+}
+
+func newPatch(boxID string, messageID string) *model.MessagePatchToPost {
+	return &model.MessagePatchToPost{
+		BoxId:     proto.String(boxID),
+		MessageId: proto.String(messageID),
+	}
+}
+
+func signerInfo(s signer.Signer) (*model.Signer, error) {
+	certificate := s.Certificate()
+	if certificate == nil {
+		return nil, errors.New("подписант не вернул сертификат")
+	}
+	return &model.Signer{
+		SignerCertificate: certificate.Raw,
+	}, nil
+}
+
+func signContent(ctx context.Context, s signer.Signer, data []byte) (*model.SignedContent, error) {
+	signature, err := s.Sign(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	return &model.SignedContent{
+		Content:   data,
+		Signature: signature,
+	}, nil
+}
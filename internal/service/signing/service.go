@@ -0,0 +1,142 @@
+package signing
+
+import (
+	"context"
+	"github.com/DimaSSV/diadocclient/internal/adapter"
+	"github.com/DimaSSV/diadocclient/internal/service/document"
+	"github.com/DimaSSV/diadocclient/internal/service/message"
+	"github.com/DimaSSV/diadocclient/internal/service/template"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"github.com/DimaSSV/diadocclient/pkg/signer"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// SignMessageToPost подписывает все вложения SignedContent сообщения, у которых еще нет подписи
+func SignMessageToPost(ctx context.Context, a *adapter.Adapter, post *model.MessageToPost, s signer.Signer) error {
+	return signContents(ctx, a, post.ProtoReflect(), s)
+}
+
+// SignMessagePatchToPost подписывает вложения SignedContent патча, а также подписи DocumentSignature
+// без заполненного поля Signature: подписывается содержимое сущности ParentEntityId
+// (или PatchedContentId, если он указан)
+func SignMessagePatchToPost(ctx context.Context, a *adapter.Adapter, patch *model.MessagePatchToPost, s signer.Signer) error {
+	if err := signContents(ctx, a, patch.ProtoReflect(), s); err != nil {
+		return err
+	}
+	for _, signature := range patch.Signatures {
+		if signature.Signature != nil || signature.GetSignWithTestSignature() || signature.GetSignatureNameOnShelf() != "" {
+			continue
+		}
+		entityID := signature.GetParentEntityId()
+		if signature.GetPatchedContentId() != "" {
+			entityID = signature.GetPatchedContentId()
+		}
+		data, err := message.GetEntityContent(ctx, a, patch.GetBoxId(), patch.GetMessageId(), entityID)
+		if err != nil {
+			return err
+		}
+		signature.Signature, err = s.Sign(ctx, data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SignDraftToSend подписывает документы черновика, для которых в DocumentSignatures не передана подпись
+func SignDraftToSend(ctx context.Context, a *adapter.Adapter, send *model.DraftToSend, s signer.Signer) error {
+	for _, signature := range send.DocumentSignatures {
+		if signature.Signature != nil || signature.GetSignWithTestSignature() {
+			continue
+		}
+		entityID := signature.GetParentEntityId()
+		if signature.GetPatchedContentId() != "" {
+			entityID = signature.GetPatchedContentId()
+		}
+		data, err := message.GetEntityContent(ctx, a, send.GetBoxId(), send.GetDraftId(), entityID)
+		if err != nil {
+			return err
+		}
+		signature.Signature, err = s.Sign(ctx, data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SignTemplatePatchToPost подписывает вложения SignedContent патча шаблона
+func SignTemplatePatchToPost(ctx context.Context, a *adapter.Adapter, post *model.TemplatePatchToPost, s signer.Signer) error {
+	return signContents(ctx, a, post.ProtoReflect(), s)
+}
+
+func PostMessage(ctx context.Context, a *adapter.Adapter, operationID string, post *model.MessageToPost, s signer.Signer) (*model.Message, error) {
+	if err := SignMessageToPost(ctx, a, post, s); err != nil {
+		return nil, err
+	}
+	return message.PostMessage(ctx, a, operationID, post)
+}
+
+func PostMessagePatch(ctx context.Context, a *adapter.Adapter, operationID string, patch *model.MessagePatchToPost, s signer.Signer) (*model.MessagePatch, error) {
+	if err := SignMessagePatchToPost(ctx, a, patch, s); err != nil {
+		return nil, err
+	}
+	return message.PostMessagePatch(ctx, a, operationID, patch)
+}
+
+func SendDraft(ctx context.Context, a *adapter.Adapter, operationID string, send *model.DraftToSend, s signer.Signer) (*model.Message, error) {
+	if err := SignDraftToSend(ctx, a, send, s); err != nil {
+		return nil, err
+	}
+	return document.SendDraft(ctx, a, operationID, send)
+}
+
+func PostTemplatePatch(ctx context.Context, a *adapter.Adapter, boxID string, templateID string, operationID string, post *model.TemplatePatchToPost, s signer.Signer) (*model.MessagePatch, error) {
+	if err := SignTemplatePatchToPost(ctx, a, post, s); err != nil {
+		return nil, err
+	}
+	return template.PostTemplatePatch(ctx, a, boxID, templateID, operationID, post)
+}
+
+// signContents обходит сообщение и подписывает каждое вложенное SignedContent
+func signContents(ctx context.Context, a *adapter.Adapter, m protoreflect.Message, s signer.Signer) error {
+	if content, ok := m.Interface().(*model.SignedContent); ok {
+		return signContent(ctx, a, content, s)
+	}
+	var err error
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() == nil || fd.IsMap() {
+			return true
+		}
+		if fd.IsList() {
+			list := v.List()
+			for i := 0; i < list.Len() && err == nil; i++ {
+				err = signContents(ctx, a, list.Get(i).Message(), s)
+			}
+		} else {
+			err = signContents(ctx, a, v.Message(), s)
+		}
+		return err == nil
+	})
+	return err
+}
+
+func signContent(ctx context.Context, a *adapter.Adapter, content *model.SignedContent, s signer.Signer) error {
+	if content.Signature != nil || content.GetSignWithTestSignature() || content.GetSignatureNameOnShelf() != "" {
+		return nil
+	}
+	data := content.Content
+	if data == nil && content.GetNameOnShelf() != "" {
+		var err error
+		data, err = document.ShelfDownload(ctx, a, content.GetNameOnShelf())
+		if err != nil {
+			return err
+		}
+	}
+	signature, err := s.Sign(ctx, data)
+	if err != nil {
+		return err
+	}
+	content.Signature = signature
+	return nil
+}
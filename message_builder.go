@@ -0,0 +1,238 @@
+package diadocсlient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"github.com/DimaSSV/diadocclient/pkg/signer"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+// MessageBuilder собирает MessageToPost только через поле DocumentAttachments,
+// не затрагивая устаревшие поля (Invoices, XmlTorg12SellerTitles, UniversalTransferDocumentSellerTitles и т.д.)
+type MessageBuilder struct {
+	post   model.MessageToPost
+	signer signer.Signer
+}
+
+// AttachmentBuilder собирает DocumentAttachment для MessageBuilder.Attach. Создается через NewAttachment
+// или NewAttachmentFromShelf; у нулевого значения не заданы тип и содержимое, и Build его отклонит
+type AttachmentBuilder struct {
+	attachment model.DocumentAttachment
+}
+
+func NewMessage(fromBoxID string) *MessageBuilder {
+	return &MessageBuilder{
+		post: model.MessageToPost{FromBoxId: proto.String(fromBoxID)},
+	}
+}
+
+func (b *MessageBuilder) FromDepartment(departmentID string) *MessageBuilder {
+	b.post.FromDepartmentId = proto.String(departmentID)
+	return b
+}
+
+// To задает ящик получателя и, если departmentID не пустой, подразделение получателя
+func (b *MessageBuilder) To(boxID string, departmentID string) *MessageBuilder {
+	b.post.ToBoxId = proto.String(boxID)
+	if departmentID != "" {
+		b.post.ToDepartmentId = proto.String(departmentID)
+	}
+	return b
+}
+
+// Proxy задает промежуточного получателя
+func (b *MessageBuilder) Proxy(boxID string, departmentID string) *MessageBuilder {
+	b.post.ProxyBoxId = proto.String(boxID)
+	if departmentID != "" {
+		b.post.ProxyDepartmentId = proto.String(departmentID)
+	}
+	return b
+}
+
+func (b *MessageBuilder) Attach(attachments ...*AttachmentBuilder) *MessageBuilder {
+	for _, attachment := range attachments {
+		b.post.DocumentAttachments = append(b.post.DocumentAttachments, proto.Clone(&attachment.attachment).(*model.DocumentAttachment))
+	}
+	return b
+}
+
+func (b *MessageBuilder) AsDraft() *MessageBuilder {
+	b.post.IsDraft = proto.Bool(true)
+	return b
+}
+
+func (b *MessageBuilder) LockDraft() *MessageBuilder {
+	b.post.LockDraft = proto.Bool(true)
+	return b
+}
+
+func (b *MessageBuilder) LockPacket() *MessageBuilder {
+	b.post.LockPacket = proto.Bool(true)
+	return b
+}
+
+func (b *MessageBuilder) LockMode(mode model.LockMode) *MessageBuilder {
+	b.post.LockMode = mode.Enum()
+	return b
+}
+
+func (b *MessageBuilder) Internal() *MessageBuilder {
+	b.post.IsInternal = proto.Bool(true)
+	return b
+}
+
+func (b *MessageBuilder) DelaySend() *MessageBuilder {
+	b.post.DelaySend = proto.Bool(true)
+	return b
+}
+
+// SignWith задает подписанта, которым при отправке подписываются вложения без подписи
+func (b *MessageBuilder) SignWith(s signer.Signer) *MessageBuilder {
+	b.signer = s
+	return b
+}
+
+// Build проверяет заполнение обязательных полей и возвращает копию собранного сообщения
+func (b *MessageBuilder) Build() (*model.MessageToPost, error) {
+	if len(b.post.DocumentAttachments) == 0 {
+		return nil, errors.New("в сообщении нет документов")
+	}
+	if b.post.GetToBoxId() == "" && !b.post.GetIsDraft() && !b.post.GetIsInternal() {
+		return nil, errors.New("не указан ящик получателя")
+	}
+	for i, attachment := range b.post.DocumentAttachments {
+		if attachment.GetTypeNamedId() == "" {
+			return nil, fmt.Errorf("документ %d: не задан тип документа", i+1)
+		}
+		content := attachment.GetSignedContent()
+		if content.GetContent() == nil && content.GetNameOnShelf() == "" {
+			return nil, fmt.Errorf("документ %d: не задано содержимое", i+1)
+		}
+		if b.signer == nil && !b.post.GetIsDraft() && content.GetSignature() == nil &&
+			content.GetSignatureNameOnShelf() == "" && !content.GetSignWithTestSignature() {
+			return nil, fmt.Errorf("документ %d: не задана подпись", i+1)
+		}
+	}
+	post := proto.Clone(&b.post).(*model.MessageToPost)
+	if err := proto.CheckInitialized(post); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// SendMessage собирает сообщение и отправляет его через PostMessage со сгенерированным operationId
+func (c DiadocClient) SendMessage(ctx context.Context, b *MessageBuilder) (*model.Message, error) {
+	post, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	if b.signer != nil {
+		return c.SignAndPostMessage(ctx, uuid.NewString(), post, b.signer)
+	}
+	return c.PostMessage(ctx, uuid.NewString(), post)
+}
+
+// NewAttachment создает документ с типом typeNamedID (например, "UniversalTransferDocument")
+func NewAttachment(typeNamedID string, content []byte) *AttachmentBuilder {
+	return &AttachmentBuilder{
+		attachment: model.DocumentAttachment{
+			TypeNamedId:   proto.String(typeNamedID),
+			SignedContent: &model.SignedContent{Content: content},
+		},
+	}
+}
+
+// NewAttachmentFromShelf создает документ, содержимое которого предварительно загружено на полку
+func NewAttachmentFromShelf(typeNamedID string, nameOnShelf string) *AttachmentBuilder {
+	return &AttachmentBuilder{
+		attachment: model.DocumentAttachment{
+			TypeNamedId:   proto.String(typeNamedID),
+			SignedContent: &model.SignedContent{NameOnShelf: proto.String(nameOnShelf)},
+		},
+	}
+}
+
+// Format задает функцию и версию формата документа
+func (a *AttachmentBuilder) Format(function string, version string) *AttachmentBuilder {
+	if function != "" {
+		a.attachment.Function = proto.String(function)
+	}
+	if version != "" {
+		a.attachment.Version = proto.String(version)
+	}
+	return a
+}
+
+func (a *AttachmentBuilder) Signature(signature []byte) *AttachmentBuilder {
+	a.signedContent().Signature = signature
+	return a
+}
+
+func (a *AttachmentBuilder) SignWithTestSignature() *AttachmentBuilder {
+	a.signedContent().SignWithTestSignature = proto.Bool(true)
+	return a
+}
+
+// signedContent возвращает SignedContent вложения, создавая его для нулевого AttachmentBuilder
+func (a *AttachmentBuilder) signedContent() *model.SignedContent {
+	if a.attachment.SignedContent == nil {
+		a.attachment.SignedContent = &model.SignedContent{}
+	}
+	return a.attachment.SignedContent
+}
+
+func (a *AttachmentBuilder) Comment(comment string) *AttachmentBuilder {
+	a.attachment.Comment = proto.String(comment)
+	return a
+}
+
+func (a *AttachmentBuilder) NeedRecipientSignature() *AttachmentBuilder {
+	a.attachment.NeedRecipientSignature = proto.Bool(true)
+	return a
+}
+
+func (a *AttachmentBuilder) NeedReceipt() *AttachmentBuilder {
+	a.attachment.NeedReceipt = proto.Bool(true)
+	return a
+}
+
+func (a *AttachmentBuilder) CustomDocumentID(id string) *AttachmentBuilder {
+	a.attachment.CustomDocumentId = proto.String(id)
+	return a
+}
+
+func (a *AttachmentBuilder) Metadata(key string, value string) *AttachmentBuilder {
+	a.attachment.Metadata = append(a.attachment.Metadata, &model.MetadataItem{
+		Key:   proto.String(key),
+		Value: proto.String(value),
+	})
+	return a
+}
+
+func (a *AttachmentBuilder) CustomData(key string, value string) *AttachmentBuilder {
+	a.attachment.CustomData = append(a.attachment.CustomData, &model.CustomDataItem{
+		Key:   proto.String(key),
+		Value: proto.String(value),
+	})
+	return a
+}
+
+// InitialDocument связывает документ с исходным документом (например, исправление с УПД)
+func (a *AttachmentBuilder) InitialDocument(messageID string, entityID string) *AttachmentBuilder {
+	a.attachment.InitialDocumentIds = append(a.attachment.InitialDocumentIds, &model.DocumentId{
+		MessageId: proto.String(messageID),
+		EntityId:  proto.String(entityID),
+	})
+	return a
+}
+
+func (a *AttachmentBuilder) SubordinateDocument(messageID string, entityID string) *AttachmentBuilder {
+	a.attachment.SubordinateDocumentIds = append(a.attachment.SubordinateDocumentIds, &model.DocumentId{
+		MessageId: proto.String(messageID),
+		EntityId:  proto.String(entityID),
+	})
+	return a
+}
@@ -0,0 +1,432 @@
+package diadocсlient
+
+import (
+	"context"
+	"errors"
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"google.golang.org/protobuf/proto"
+	"sync"
+)
+
+const (
+	// forwardedDocumentsBatchSize количество идентификаторов в одном запросе GetForwardedDocuments по умолчанию
+	forwardedDocumentsBatchSize = 100
+	// searchDocflowsPageSize размер страницы SearchDocflows, если request.Count не задан
+	searchDocflowsPageSize = 100
+	// employeesPageSize максимальный размер страницы GetEmployees
+	employeesPageSize = 50
+	// departmentsPageSize максимальный размер страницы GetDepartmentsFull
+	departmentsPageSize = 100
+	// listAllConcurrency количество одновременно загружаемых страниц в ListAll*
+	listAllConcurrency = 4
+	// listAllAttempts количество попыток получить согласованный список в ListAll*
+	listAllAttempts = 3
+)
+
+// errInconsistentList список изменялся во время постраничной загрузки
+var errInconsistentList = errors.New("список изменялся во время загрузки, согласованный снимок получить не удалось")
+
+// pageResult страница, полученная загрузчиком
+type pageResult[T any] struct {
+	items []T
+	more  bool
+	total int32
+	// hasTotal ответ метода содержит общее количество элементов
+	hasTotal bool
+	err      error
+}
+
+// Pager постранично обходит результаты метода API:
+//
+//	p := client.SearchDocflowsPager(boxID, request).Prefetch(2)
+//	defer p.Close()
+//	for p.Next(ctx) {
+//		item := p.Item()
+//	}
+//	if err := p.Err(); err != nil {
+//	}
+type Pager[T any] struct {
+	// load загружает страницу с номером n. Для постраничных методов с ключом (AfterIndexKey)
+	// вызывается строго последовательно и хранит ключ в замыкании
+	load func(ctx context.Context, n int) pageResult[T]
+	// independent страницы можно загружать одновременно (FirstIndex, пакеты идентификаторов)
+	independent bool
+	prefetch    int
+
+	page     int
+	items    []T
+	current  T
+	total    int32
+	hasTotal bool
+	done     bool
+	err      error
+	results  chan pageResult[T]
+	cancel   context.CancelFunc
+}
+
+// Prefetch включает загрузку до n страниц заранее в фоне. Если страницы независимы (SearchDocflows,
+// GetForwardedDocuments), они загружаются одновременно, иначе - последовательно, пока обрабатывается текущая.
+// Фоновая загрузка использует контекст первого вызова Next; при досрочном завершении обхода нужно вызвать Close
+func (p *Pager[T]) Prefetch(n int) *Pager[T] {
+	p.prefetch = n
+	return p
+}
+
+// Next переходит к следующему элементу. Возвращает false по окончании результатов или при ошибке
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	for len(p.items) == 0 {
+		if p.done || p.err != nil {
+			return false
+		}
+		p.apply(p.nextPage(ctx))
+	}
+	p.current = p.items[0]
+	p.items = p.items[1:]
+	return true
+}
+
+func (p *Pager[T]) apply(result pageResult[T]) {
+	if result.err != nil {
+		p.err = result.err
+		p.Close()
+		return
+	}
+	p.items = result.items
+	if result.hasTotal {
+		p.total = result.total
+		p.hasTotal = true
+	}
+	if !result.more {
+		p.done = true
+		p.Close()
+	}
+}
+
+func (p *Pager[T]) nextPage(ctx context.Context) pageResult[T] {
+	if p.prefetch <= 0 {
+		result := p.load(ctx, p.page)
+		p.page++
+		return result
+	}
+	if p.results == nil {
+		p.start(ctx)
+	}
+	result, ok := <-p.results
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			return pageResult[T]{err: err}
+		}
+		return pageResult[T]{}
+	}
+	return result
+}
+
+func (p *Pager[T]) start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.results = make(chan pageResult[T], p.prefetch)
+	if !p.independent {
+		go func() {
+			defer close(p.results)
+			for n := 0; ; n++ {
+				result := p.load(ctx, n)
+				select {
+				case p.results <- result:
+				case <-ctx.Done():
+					return
+				}
+				if result.err != nil || !result.more {
+					return
+				}
+			}
+		}()
+		return
+	}
+	// Страницы загружаются одновременно, но выдаются по порядку: каждой странице соответствует
+	// свой канал, очередь каналов ограничивает число одновременных загрузок
+	slots := make(chan chan pageResult[T], p.prefetch)
+	go func() {
+		defer close(slots)
+		for n := 0; ; n++ {
+			slot := make(chan pageResult[T], 1)
+			select {
+			case slots <- slot:
+			case <-ctx.Done():
+				return
+			}
+			go func(n int) {
+				slot <- p.load(ctx, n)
+			}(n)
+		}
+	}()
+	go func() {
+		defer close(p.results)
+		for slot := range slots {
+			result := <-slot
+			select {
+			case p.results <- result:
+			case <-ctx.Done():
+				return
+			}
+			if result.err != nil || !result.more {
+				return
+			}
+		}
+	}()
+}
+
+// Item возвращает текущий элемент
+func (p *Pager[T]) Item() T {
+	return p.current
+}
+
+// TotalCount возвращает общее количество элементов, если метод API его сообщает
+func (p *Pager[T]) TotalCount() (int32, bool) {
+	return p.total, p.hasTotal
+}
+
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// Close останавливает фоновую загрузку страниц
+func (p *Pager[T]) Close() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+// SearchDocflowsPager обходит результаты SearchDocflows, начиная с request.FirstIndex, страницами по request.Count (0 - по 100)
+func (c DiadocClient) SearchDocflowsPager(boxID string, request *model.SearchDocflowsRequest) *Pager[*model.DocumentWithDocflowV3] {
+	first := request.GetFirstIndex()
+	count := request.GetCount()
+	if count <= 0 {
+		count = searchDocflowsPageSize
+	}
+	return &Pager[*model.DocumentWithDocflowV3]{
+		independent: true,
+		load: func(ctx context.Context, n int) pageResult[*model.DocumentWithDocflowV3] {
+			page := proto.Clone(request).(*model.SearchDocflowsRequest)
+			page.FirstIndex = proto.Int32(first + int32(n)*count)
+			page.Count = proto.Int32(count)
+			response, err := c.SearchDocflows(ctx, boxID, page)
+			if err != nil {
+				return pageResult[*model.DocumentWithDocflowV3]{err: err}
+			}
+			return pageResult[*model.DocumentWithDocflowV3]{
+				items: response.Documents,
+				more:  response.GetHaveMoreDocuments() && len(response.Documents) > 0,
+			}
+		},
+	}
+}
+
+// ForwardedDocumentsPager получает пересланные документы по списку request.ForwardedDocumentIds,
+// разбивая его на запросы по batchSize идентификаторов (0 - по 100). Общее количество равно длине списка
+func (c DiadocClient) ForwardedDocumentsPager(boxID string, request *model.GetForwardedDocumentsRequest, batchSize int) *Pager[*model.ForwardedDocument] {
+	if batchSize <= 0 {
+		batchSize = forwardedDocumentsBatchSize
+	}
+	ids := request.ForwardedDocumentIds
+	return &Pager[*model.ForwardedDocument]{
+		independent: true,
+		load: func(ctx context.Context, n int) pageResult[*model.ForwardedDocument] {
+			from := n * batchSize
+			if from >= len(ids) {
+				return pageResult[*model.ForwardedDocument]{total: int32(len(ids)), hasTotal: true}
+			}
+			to := from + batchSize
+			if to > len(ids) {
+				to = len(ids)
+			}
+			response, err := c.GetForwardedDocuments(ctx, boxID, &model.GetForwardedDocumentsRequest{
+				ForwardedDocumentIds: ids[from:to],
+				InjectEntityContent:  request.InjectEntityContent,
+			})
+			if err != nil {
+				return pageResult[*model.ForwardedDocument]{err: err}
+			}
+			return pageResult[*model.ForwardedDocument]{
+				items:    response.ForwardedDocuments,
+				more:     to < len(ids),
+				total:    int32(len(ids)),
+				hasTotal: true,
+			}
+		},
+	}
+}
+
+// ForwardedDocumentEventsPager обходит события GetForwardedDocumentEvents, начиная с request.AfterIndexKey
+func (c DiadocClient) ForwardedDocumentEventsPager(boxID string, request *model.GetForwardedDocumentEventsRequest) *Pager[*model.ForwardedDocumentEvent] {
+	afterIndexKey := request.AfterIndexKey
+	return &Pager[*model.ForwardedDocumentEvent]{
+		load: func(ctx context.Context, n int) pageResult[*model.ForwardedDocumentEvent] {
+			page := proto.Clone(request).(*model.GetForwardedDocumentEventsRequest)
+			page.AfterIndexKey = afterIndexKey
+			response, err := c.GetForwardedDocumentEvents(ctx, boxID, page)
+			if err != nil {
+				return pageResult[*model.ForwardedDocumentEvent]{err: err}
+			}
+			if len(response.Events) > 0 {
+				afterIndexKey = response.Events[len(response.Events)-1].GetIndexKey()
+			}
+			return pageResult[*model.ForwardedDocumentEvent]{
+				items:    response.Events,
+				more:     len(response.Events) > 0,
+				total:    response.GetTotalCount(),
+				hasTotal: true,
+			}
+		},
+	}
+}
+
+// EmployeesPager обходит сотрудников ящика страницами по pageSize (0 - по 50).
+// Окончание определяется по TotalCount, страницы независимы и могут загружаться одновременно
+func (c DiadocClient) EmployeesPager(boxID string, pageSize int) *Pager[*model.Employee] {
+	if pageSize <= 0 {
+		pageSize = employeesPageSize
+	}
+	return numberedPager(pageSize, func(ctx context.Context, page int) ([]*model.Employee, int32, error) {
+		list, err := c.GetEmployees(ctx, boxID, page, pageSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		return list.Employees, list.GetTotalCount(), nil
+	})
+}
+
+// DepartmentsPager обходит подразделения ящика страницами по pageSize (0 - по 100)
+func (c DiadocClient) DepartmentsPager(boxID string, pageSize int) *Pager[*model.DepartmentAdmin] {
+	if pageSize <= 0 {
+		pageSize = departmentsPageSize
+	}
+	return numberedPager(pageSize, func(ctx context.Context, page int) ([]*model.DepartmentAdmin, int32, error) {
+		list, err := c.GetDepartmentsFull(ctx, boxID, page, pageSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		return list.Departments, list.GetTotalCount(), nil
+	})
+}
+
+// ListAllEmployees возвращает всех сотрудников ящика. Страницы после первой загружаются одновременно;
+// если за время загрузки состав сотрудников изменился, список загружается заново
+func (c DiadocClient) ListAllEmployees(ctx context.Context, boxID string) ([]*model.Employee, error) {
+	return listAll(ctx, employeesPageSize,
+		func(ctx context.Context, page int) ([]*model.Employee, int32, error) {
+			list, err := c.GetEmployees(ctx, boxID, page, employeesPageSize)
+			if err != nil {
+				return nil, 0, err
+			}
+			return list.Employees, list.GetTotalCount(), nil
+		},
+		func(employee *model.Employee) string {
+			return employee.GetUser().GetUserId()
+		})
+}
+
+// ListAllDepartments возвращает все подразделения ящика (без головного), аналогично ListAllEmployees
+func (c DiadocClient) ListAllDepartments(ctx context.Context, boxID string) ([]*model.DepartmentAdmin, error) {
+	return listAll(ctx, departmentsPageSize,
+		func(ctx context.Context, page int) ([]*model.DepartmentAdmin, int32, error) {
+			list, err := c.GetDepartmentsFull(ctx, boxID, page, departmentsPageSize)
+			if err != nil {
+				return nil, 0, err
+			}
+			return list.Departments, list.GetTotalCount(), nil
+		},
+		func(department *model.DepartmentAdmin) string {
+			return department.GetId()
+		})
+}
+
+// numberedPager создает Pager для методов с номером страницы (с 1) и общим количеством элементов в ответе
+func numberedPager[T any](pageSize int, load func(ctx context.Context, page int) ([]T, int32, error)) *Pager[T] {
+	return &Pager[T]{
+		independent: true,
+		load: func(ctx context.Context, n int) pageResult[T] {
+			items, total, err := load(ctx, n+1)
+			if err != nil {
+				return pageResult[T]{err: err}
+			}
+			return pageResult[T]{
+				items:    items,
+				more:     len(items) > 0 && (n+1)*pageSize < int(total),
+				total:    total,
+				hasTotal: true,
+			}
+		},
+	}
+}
+
+// listAll загружает первую страницу, по TotalCount определяет количество страниц и загружает остальные
+// одновременно. Снимок считается согласованным, если все страницы сообщили одинаковый TotalCount,
+// а количество различных элементов совпало с ним; иначе загрузка повторяется
+func listAll[T any](ctx context.Context, pageSize int, load func(ctx context.Context, page int) ([]T, int32, error), key func(T) string) ([]T, error) {
+	for attempt := 0; attempt < listAllAttempts; attempt++ {
+		first, total, err := load(ctx, 1)
+		if err != nil {
+			return nil, err
+		}
+		pageCount := (int(total) + pageSize - 1) / pageSize
+		if pageCount < 1 {
+			pageCount = 1
+		}
+		pages := make([][]T, pageCount+1)
+		pages[1] = first
+		consistent := true
+		if pageCount > 1 {
+			loadCtx, cancel := context.WithCancel(ctx)
+			var (
+				wg       sync.WaitGroup
+				mu       sync.Mutex
+				firstErr error
+			)
+			sem := make(chan struct{}, listAllConcurrency)
+			for page := 2; page <= pageCount && loadCtx.Err() == nil; page++ {
+				wg.Add(1)
+				sem <- struct{}{}
+				go func(page int) {
+					defer wg.Done()
+					defer func() { <-sem }()
+					items, pageTotal, err := load(loadCtx, page)
+					mu.Lock()
+					defer mu.Unlock()
+					if err != nil {
+						if firstErr == nil {
+							firstErr = err
+							cancel()
+						}
+						return
+					}
+					pages[page] = items
+					if pageTotal != total {
+						consistent = false
+					}
+				}(page)
+			}
+			wg.Wait()
+			cancel()
+			// Ошибка проверяется у внешнего контекста: loadCtx к этому моменту всегда отменен
+			if firstErr == nil {
+				firstErr = ctx.Err()
+			}
+			if firstErr != nil {
+				return nil, firstErr
+			}
+		}
+		var result []T
+		seen := make(map[string]bool, total)
+		for _, page := range pages {
+			for _, item := range page {
+				if k := key(item); !seen[k] {
+					seen[k] = true
+					result = append(result, item)
+				}
+			}
+		}
+		if consistent && len(result) == int(total) {
+			return result, nil
+		}
+	}
+	return nil, errInconsistentList
+}
@@ -0,0 +1,89 @@
+// Package async ожидает завершения асинхронных операций Диадока, возвращающих model.AsyncMethodResult:
+// метод результата опрашивается, пока задача выполняется, с паузами по Retry-After или по экспоненциальному backoff
+package async
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Backoff задает паузы между опросами результата: первая пауза Initial, каждая следующая
+// умножается на Multiplier, но не превышает Max. Заголовок Retry-After имеет приоритет
+type Backoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+var DefaultBackoff = Backoff{
+	Initial:    time.Second,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+}
+
+// Status результат одного опроса
+type Status struct {
+	// InProgress задача еще выполняется, нужно повторить запрос
+	InProgress bool
+	// RetryAfter пауза из заголовка Retry-After (0 - не задана)
+	RetryAfter time.Duration
+}
+
+// Poll выполняет один запрос результата. Ошибка означает окончательную неудачу и прекращает ожидание
+type Poll[T any] func(ctx context.Context) (T, Status, error)
+
+// Wait опрашивает poll, пока задача выполняется. Ожидание прерывается отменой контекста
+func Wait[T any](ctx context.Context, backoff Backoff, poll Poll[T]) (T, error) {
+	delay := backoff.Initial
+	if delay <= 0 {
+		delay = DefaultBackoff.Initial
+	}
+	for {
+		result, status, err := poll(ctx)
+		if err != nil || !status.InProgress {
+			return result, err
+		}
+		wait := delay
+		if status.RetryAfter > 0 {
+			wait = status.RetryAfter
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			var zero T
+			return zero, ctx.Err()
+		case <-timer.C:
+		}
+		delay = backoff.next(delay)
+	}
+}
+
+func (b Backoff) next(delay time.Duration) time.Duration {
+	if b.Multiplier > 1 {
+		delay = time.Duration(float64(delay) * b.Multiplier)
+	}
+	if b.Max > 0 && delay > b.Max {
+		delay = b.Max
+	}
+	return delay
+}
+
+// ParseRetryAfter разбирает заголовок Retry-After в секундах или в формате HTTP-даты
+func ParseRetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
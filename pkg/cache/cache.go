@@ -0,0 +1,28 @@
+// Package cache содержит хранилища для кэширования справочных данных Диадока (организации, ящики, контрагенты):
+// в памяти с вытеснением LRU и временем жизни записей, и в файлах на диске
+package cache
+
+import (
+	"time"
+)
+
+// Backend хранилище кэша. Значения - сериализованные сообщения protobuf.
+// Реализации должны быть безопасны для одновременного использования
+type Backend interface {
+	// Get возвращает значение, если оно есть и не устарело
+	Get(key string) ([]byte, bool)
+	// Set сохраняет значение на время ttl (0 - без ограничения)
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(key string)
+}
+
+func expiration(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+func expired(expiresAt time.Time) bool {
+	return !expiresAt.IsZero() && time.Now().After(expiresAt)
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// File хранилище в каталоге на диске: каждая запись - отдельный файл, имя которого - хэш ключа.
+// Файл содержит время устаревания (8 байт, UnixNano, 0 - без ограничения) и значение.
+// Подходит для сохранения кэша между запусками
+type File struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func NewFile(dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &File{dir: dir}, nil
+}
+
+func (f *File) Get(key string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, err := os.ReadFile(f.path(key))
+	if err != nil || len(data) < 8 {
+		return nil, false
+	}
+	var expiresAt time.Time
+	if nanos := int64(binary.BigEndian.Uint64(data)); nanos != 0 {
+		expiresAt = time.Unix(0, nanos)
+	}
+	if expired(expiresAt) {
+		_ = os.Remove(f.path(key))
+		return nil, false
+	}
+	return data[8:], true
+}
+
+func (f *File) Set(key string, value []byte, ttl time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data := make([]byte, 8, 8+len(value))
+	if expiresAt := expiration(ttl); !expiresAt.IsZero() {
+		binary.BigEndian.PutUint64(data, uint64(expiresAt.UnixNano()))
+	}
+	data = append(data, value...)
+	// Запись через временный файл, чтобы при сбое не оставить поврежденную запись
+	tmp, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err = os.Rename(tmp.Name(), f.path(key)); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+func (f *File) Delete(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_ = os.Remove(f.path(key))
+}
+
+func (f *File) path(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,89 @@
+// Package credentials содержит источники учетных данных Диадока. Источник опрашивается при каждом входе,
+// поэтому смена пароля в хранилище секретов вступает в силу без перезапуска процесса
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+)
+
+// Credentials учетные данные для входа в Диадок
+type Credentials struct {
+	Login    string `json:"login" yaml:"login"`
+	Password string `json:"password" yaml:"password"`
+	ClientID string `json:"clientId" yaml:"clientId"`
+}
+
+// Validate проверяет, что заполнены все поля
+func (c Credentials) Validate() error {
+	if c.Login == "" || c.Password == "" || c.ClientID == "" {
+		return errors.New("credentials: не заполнены логин, пароль или идентификатор клиента API")
+	}
+	return nil
+}
+
+// Provider источник учетных данных. Вызывается при каждом получении токена, реализации
+// должны быть безопасны для одновременного использования
+type Provider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// Static неизменяемые учетные данные
+type Static Credentials
+
+func (s Static) Credentials(context.Context) (Credentials, error) {
+	return Credentials(s), nil
+}
+
+// Func источник на основе функции, например обращения к внешнему хранилищу секретов
+type Func func(ctx context.Context) (Credentials, error)
+
+func (f Func) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// Env читает учетные данные из переменных окружения <Prefix>_LOGIN, <Prefix>_PASSWORD и <Prefix>_CLIENT_ID.
+// Пустой Prefix означает DIADOC
+type Env struct {
+	Prefix string
+}
+
+func (e Env) Credentials(context.Context) (Credentials, error) {
+	prefix := e.Prefix
+	if prefix == "" {
+		prefix = "DIADOC"
+	}
+	result := Credentials{
+		Login:    os.Getenv(prefix + "_LOGIN"),
+		Password: os.Getenv(prefix + "_PASSWORD"),
+		ClientID: os.Getenv(prefix + "_CLIENT_ID"),
+	}
+	if err := result.Validate(); err != nil {
+		return Credentials{}, fmt.Errorf("%w (переменные окружения %s_*)", err, prefix)
+	}
+	return result, nil
+}
+
+// File читает учетные данные из файла YAML или JSON с полями login, password и clientId.
+// Файл перечитывается при каждом входе
+type File struct {
+	Path string
+}
+
+func (f File) Credentials(context.Context) (Credentials, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return Credentials{}, err
+	}
+	var result Credentials
+	if err = yaml.Unmarshal(data, &result); err != nil {
+		return Credentials{}, fmt.Errorf("credentials: %s: %w", f.Path, err)
+	}
+	if err = result.Validate(); err != nil {
+		return Credentials{}, fmt.Errorf("%w (%s)", err, f.Path)
+	}
+	return result, nil
+}
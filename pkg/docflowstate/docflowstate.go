@@ -0,0 +1,242 @@
+// Package docflowstate сводит состояние документооборота (DocflowV3 и устаревший Docflow)
+// к типизированному этапу жизненного цикла документа и списку допустимых действий
+package docflowstate
+
+import (
+	"github.com/DimaSSV/diadocclient/pkg/model"
+)
+
+// State - этап жизненного цикла документа с точки зрения текущего ящика
+type State int
+
+const (
+	Unknown State = iota
+	// AwaitingSenderSignature - документ еще не подписан отправителем
+	AwaitingSenderSignature
+	// InvalidSenderSignature - подпись отправителя не прошла проверку
+	InvalidSenderSignature
+	// AwaitingOurSignature - входящий документ ожидает нашей подписи или отказа
+	AwaitingOurSignature
+	// AwaitingCounteragentSignature - исходящий документ ожидает подписи контрагента
+	AwaitingCounteragentSignature
+	// InProgress - документ не требует подписи получателя, документооборот еще не завершен (извещения, подтверждения)
+	InProgress
+	Signed
+	Rejected
+	InvalidRecipientSignature
+	RevocationRequestedByUs
+	RevocationRequestedByCounteragent
+	Revoked
+	// Finished - документооборот по документу, не требующему подписи получателя, завершен
+	Finished
+	// CorrectionRequested - получатель запросил уточнение документа (AmendmentRequest)
+	CorrectionRequested
+	// AwaitingResolution - документ ожидает внутреннего согласования или подписи по запросу резолюции
+	AwaitingResolution
+	// RejectedByResolution - в согласовании или подписи по запросу резолюции отказано
+	RejectedByResolution
+)
+
+var stateNames = map[State]string{
+	Unknown:                           "Unknown",
+	AwaitingSenderSignature:           "AwaitingSenderSignature",
+	InvalidSenderSignature:            "InvalidSenderSignature",
+	AwaitingOurSignature:              "AwaitingOurSignature",
+	AwaitingCounteragentSignature:     "AwaitingCounteragentSignature",
+	InProgress:                        "InProgress",
+	Signed:                            "Signed",
+	Rejected:                          "Rejected",
+	InvalidRecipientSignature:         "InvalidRecipientSignature",
+	RevocationRequestedByUs:           "RevocationRequestedByUs",
+	RevocationRequestedByCounteragent: "RevocationRequestedByCounteragent",
+	Revoked:                           "Revoked",
+	Finished:                          "Finished",
+	CorrectionRequested:               "CorrectionRequested",
+	AwaitingResolution:                "AwaitingResolution",
+	RejectedByResolution:              "RejectedByResolution",
+}
+
+func (s State) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// IsFinal сообщает, что документооборот по документу больше не изменит этап
+// (кроме аннулирования, которое возможно и после завершения)
+func (s State) IsFinal() bool {
+	switch s {
+	case Signed, Rejected, Revoked, Finished:
+		return true
+	}
+	return false
+}
+
+// Action - действие, которое текущий ящик может выполнить с документом.
+// Действия соответствуют методам DiadocClient из раздела "Ответные действия по документам"
+type Action string
+
+const (
+	ActionSignAsSender      Action = "SignAsSender"
+	ActionSign              Action = "Sign"
+	ActionReject            Action = "Reject"
+	ActionRequestCorrection Action = "RequestCorrection"
+	ActionSendReceipt       Action = "SendReceipt"
+	ActionRequestRevocation Action = "RequestRevocation"
+	ActionAcceptRevocation  Action = "AcceptRevocation"
+	ActionRejectRevocation  Action = "RejectRevocation"
+)
+
+// Snapshot - состояние документа в конкретный момент
+type Snapshot struct {
+	State     State
+	Direction model.DocumentDirection
+	// ReceiptRequired - требуется отправить извещение о получении
+	ReceiptRequired bool
+	// IsFinished - документооборот завершен полностью (включая извещения)
+	IsFinished bool
+	Actions    []Action
+	// StatusText и StatusSeverity - основной статус документооборота в формулировке Диадока (DocflowStatus)
+	StatusText     string
+	StatusSeverity string
+	// SecondaryStatusText - дополнительный статус документооборота, если есть
+	SecondaryStatusText string
+}
+
+// Can сообщает, допустимо ли действие action
+func (s Snapshot) Can(action Action) bool {
+	for _, a := range s.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// FromDocumentV3 вычисляет состояние документа, полученного методами GetDocflows_V3, GetDocflowEvents_V3 и т.п.
+func FromDocumentV3(doc *model.DocumentWithDocflowV3) Snapshot {
+	return FromDocflowV3(doc.GetDocflow(), doc.GetDocumentInfo().GetDocumentDirection())
+}
+
+// FromDocflowV3 вычисляет состояние по DocflowV3. Направление документа в DocflowV3 не передается,
+// его нужно взять из DocumentInfoV3
+func FromDocflowV3(docflow *model.DocflowV3, direction model.DocumentDirection) Snapshot {
+	snapshot := Snapshot{Direction: direction}
+	if docflow == nil {
+		return snapshot
+	}
+	inbound := direction == model.DocumentDirection_Inbound
+	response := docflow.GetRecipientResponse()
+
+	switch docflow.GetSenderTitle().GetSenderSignatureStatus() {
+	case model.SenderSignatureStatus_WaitingForSenderSignature:
+		snapshot.State = AwaitingSenderSignature
+	case model.SenderSignatureStatus_SenderSignatureCheckedAndInvalid:
+		snapshot.State = InvalidSenderSignature
+	default:
+		switch response.GetResponseStatus() {
+		case model.RecipientResponseStatus_WaitingForRecipientSignature:
+			if inbound {
+				snapshot.State = AwaitingOurSignature
+			} else {
+				snapshot.State = AwaitingCounteragentSignature
+			}
+		case model.RecipientResponseStatus_WithRecipientSignature, model.RecipientResponseStatus_WithRecipientPartiallySignature:
+			snapshot.State = Signed
+		case model.RecipientResponseStatus_RecipientSignatureRequestRejected:
+			snapshot.State = Rejected
+		case model.RecipientResponseStatus_InvalidRecipientSignature:
+			snapshot.State = InvalidRecipientSignature
+		default:
+			if docflow.GetSenderTitle().GetIsFinished() {
+				snapshot.State = Finished
+			} else {
+				snapshot.State = InProgress
+			}
+		}
+	}
+
+	if amendment := docflow.GetAmendmentRequest(); amendment != nil && amendment.GetAmendmentRequest() != nil {
+		switch snapshot.State {
+		case AwaitingOurSignature, AwaitingCounteragentSignature, InProgress, Rejected:
+			snapshot.State = CorrectionRequested
+		}
+	}
+
+	if resolution := docflow.GetResolution(); resolution != nil {
+		switch snapshot.State {
+		case AwaitingSenderSignature, AwaitingOurSignature:
+			switch resolution.GetResolutionStatus().GetType() {
+			case model.ResolutionStatusType_ApprovementRequestedResolutionStatusType,
+				model.ResolutionStatusType_SignatureRequestedResolutionStatusType,
+				model.ResolutionStatusType_ActionsRequestedResolutionStatusType:
+				snapshot.State = AwaitingResolution
+			case model.ResolutionStatusType_DisapprovedResolutionStatusType,
+				model.ResolutionStatusType_SignatureDeniedResolutionStatusType:
+				snapshot.State = RejectedByResolution
+			}
+		}
+	}
+
+	if revocation := docflow.GetRevocation(); revocation != nil {
+		switch revocation.GetRevocationStatus() {
+		case model.RevocationStatus_RevocationIsRequestedByMe:
+			snapshot.State = RevocationRequestedByUs
+		case model.RevocationStatus_RequestsMyRevocation:
+			snapshot.State = RevocationRequestedByCounteragent
+		case model.RevocationStatus_RevocationAccepted:
+			snapshot.State = Revoked
+		}
+	}
+
+	receipt := docflow.GetSenderReceipt()
+	if inbound {
+		receipt = docflow.GetRecipientReceipt()
+	}
+	snapshot.ReceiptRequired = receipt.GetStatus() == model.GeneralReceiptStatus_HaveToCreateReceipt
+	snapshot.IsFinished = docflow.GetSenderTitle().GetIsFinished() &&
+		(response == nil || response.GetIsFinished()) &&
+		(docflow.GetRevocation() == nil || docflow.GetRevocation().GetIsFinished()) &&
+		(docflow.GetAmendmentRequest() == nil || docflow.GetAmendmentRequest().GetIsFinished()) &&
+		!snapshot.ReceiptRequired
+	snapshot.Actions = actions(snapshot)
+	if status := docflow.GetDocflowStatus(); status != nil {
+		snapshot.StatusText = status.GetPrimaryStatus().GetStatusText()
+		snapshot.StatusSeverity = status.GetPrimaryStatus().GetSeverity()
+		snapshot.SecondaryStatusText = status.GetSecondaryStatus().GetStatusText()
+	}
+	return snapshot
+}
+
+// actions определяет допустимые действия по этапу и направлению
+func actions(s Snapshot) []Action {
+	var result []Action
+	inbound := s.Direction == model.DocumentDirection_Inbound
+	switch s.State {
+	case AwaitingSenderSignature:
+		if !inbound {
+			result = append(result, ActionSignAsSender)
+		}
+	case AwaitingOurSignature:
+		result = append(result, ActionSign, ActionReject, ActionRequestCorrection)
+	case AwaitingResolution:
+		if inbound {
+			result = append(result, ActionSign, ActionReject, ActionRequestCorrection)
+		} else {
+			result = append(result, ActionSignAsSender)
+		}
+	case RejectedByResolution:
+		if inbound {
+			result = append(result, ActionReject, ActionRequestCorrection)
+		}
+	case AwaitingCounteragentSignature, InProgress, Signed, Finished, CorrectionRequested:
+		result = append(result, ActionRequestRevocation)
+	case RevocationRequestedByCounteragent:
+		result = append(result, ActionAcceptRevocation, ActionRejectRevocation)
+	}
+	if s.ReceiptRequired {
+		result = append(result, ActionSendReceipt)
+	}
+	return result
+}
@@ -0,0 +1,178 @@
+package docflowstate
+
+import (
+	"github.com/DimaSSV/diadocclient/pkg/model"
+)
+
+// legacyFlags - общие для всех видов устаревшего Docflow признаки
+type legacyFlags struct {
+	known                 bool
+	isFinished            bool
+	signatureRequested    bool
+	signedByRecipient     bool
+	rejectedByRecipient   bool
+	canBeSignedBySender   bool
+	canBeSignedOrRejected bool
+	receiptRequested      bool
+	canBeReceipted        bool
+	isUnilateral          bool
+}
+
+// FromDocumentWithDocflow вычисляет состояние документа, полученного устаревшими методами GetDocflows, GetDocflowEvents и т.п.
+// boxID - ящик, от имени которого запрошен документооборот, нужен для определения инициатора аннулирования
+func FromDocumentWithDocflow(doc *model.DocumentWithDocflow, boxID string) Snapshot {
+	return FromDocflow(doc.GetDocflow(), doc.GetDocumentInfo().GetDocumentDirection(), boxID)
+}
+
+// FromDocflow вычисляет состояние по устаревшей структуре Docflow
+func FromDocflow(docflow *model.Docflow, direction model.DocumentDirection, boxID string) Snapshot {
+	snapshot := Snapshot{Direction: direction}
+	if docflow == nil {
+		return snapshot
+	}
+	inbound := direction == model.DocumentDirection_Inbound
+	flags := legacyDocflowFlags(docflow)
+
+	switch {
+	case !flags.known:
+		if docflow.GetIsFinished() {
+			snapshot.State = Finished
+		} else {
+			snapshot.State = InProgress
+		}
+	case flags.canBeSignedBySender:
+		snapshot.State = AwaitingSenderSignature
+	case flags.signedByRecipient:
+		snapshot.State = Signed
+	case flags.rejectedByRecipient:
+		snapshot.State = Rejected
+	case flags.signatureRequested && !flags.isUnilateral:
+		if inbound {
+			snapshot.State = AwaitingOurSignature
+		} else {
+			snapshot.State = AwaitingCounteragentSignature
+		}
+	case flags.isFinished:
+		snapshot.State = Finished
+	default:
+		snapshot.State = InProgress
+	}
+
+	if revocation := docflow.GetRevocationDocflow(); revocation != nil {
+		switch {
+		case revocation.GetIsRevocationAccepted():
+			snapshot.State = Revoked
+		case revocation.GetIsRevocationRejected():
+		case revocation.GetInitiatorBoxId() == boxID:
+			snapshot.State = RevocationRequestedByUs
+		default:
+			snapshot.State = RevocationRequestedByCounteragent
+		}
+	}
+
+	snapshot.ReceiptRequired = flags.receiptRequested && flags.canBeReceipted
+	snapshot.IsFinished = docflow.GetIsFinished() && !snapshot.ReceiptRequired &&
+		(docflow.GetRevocationDocflow() == nil || docflow.GetRevocationDocflow().GetIsFinished())
+	snapshot.Actions = actions(snapshot)
+	if snapshot.State == AwaitingOurSignature && !flags.canBeSignedOrRejected {
+		snapshot.Actions = removeActions(snapshot.Actions, ActionSign, ActionReject)
+	}
+	return snapshot
+}
+
+func legacyDocflowFlags(docflow *model.Docflow) legacyFlags {
+	if d := docflow.GetXmlBilateralDocflow(); d != nil {
+		return legacyFlags{
+			known:                 true,
+			isFinished:            d.GetIsFinished(),
+			signatureRequested:    true,
+			signedByRecipient:     d.GetIsDocumentSignedByRecipient(),
+			rejectedByRecipient:   d.GetIsDocumentRejectedByRecipient(),
+			canBeSignedBySender:   d.GetCanDocumentBeSignedBySender(),
+			canBeSignedOrRejected: d.GetCanDocumentBeSignedOrRejectedByRecipient(),
+			receiptRequested:      d.GetIsReceiptRequested(),
+			canBeReceipted:        d.GetCanDocumentBeReceipted(),
+		}
+	}
+	if d := docflow.GetBilateralDocflow(); d != nil {
+		return legacyFlags{
+			known:                 true,
+			isFinished:            d.GetIsFinished(),
+			signatureRequested:    d.GetIsRecipientSignatureRequested(),
+			signedByRecipient:     d.GetIsDocumentSignedByRecipient(),
+			rejectedByRecipient:   d.GetIsDocumentRejectedByRecipient(),
+			canBeSignedBySender:   d.GetCanDocumentBeSignedBySender(),
+			canBeSignedOrRejected: d.GetCanDocumentBeSignedOrRejectedByRecipient(),
+			receiptRequested:      d.GetIsReceiptRequested(),
+			canBeReceipted:        d.GetCanDocumentBeReceipted(),
+		}
+	}
+	if d := docflow.GetUnilateralDocflow(); d != nil {
+		return legacyFlags{
+			known:               true,
+			isFinished:          d.GetIsFinished(),
+			isUnilateral:        true,
+			canBeSignedBySender: d.GetCanDocumentBeSignedBySender(),
+			receiptRequested:    d.GetIsReceiptRequested(),
+			canBeReceipted:      d.GetCanDocumentBeReceipted(),
+		}
+	}
+	if d := docflow.GetInboundUniversalTransferDocumentDocflow(); d != nil {
+		return legacyFlags{
+			known:                 true,
+			isFinished:            d.GetIsFinished(),
+			signatureRequested:    d.GetIsRecipientSignatureRequested(),
+			signedByRecipient:     d.GetIsDocumentSignedByRecipient(),
+			rejectedByRecipient:   d.GetIsDocumentRejectedByRecipient(),
+			canBeSignedOrRejected: d.GetCanDocumentBeSignedOrRejectedByRecipient(),
+			receiptRequested:      d.GetIsReceiptRequested(),
+			canBeReceipted:        d.GetCanDocumentBeReceipted(),
+		}
+	}
+	if d := docflow.GetOutboundUniversalTransferDocumentDocflow(); d != nil {
+		return legacyFlags{
+			known:                 true,
+			isFinished:            d.GetIsFinished(),
+			signatureRequested:    d.GetIsRecipientSignatureRequested(),
+			signedByRecipient:     d.GetIsDocumentSignedByRecipient(),
+			rejectedByRecipient:   d.GetIsDocumentRejectedByRecipient(),
+			canBeSignedBySender:   d.GetCanDocumentBeSignedBySender(),
+			canBeSignedOrRejected: d.GetCanDocumentBeSignedOrRejectedByRecipient(),
+			receiptRequested:      d.GetIsReceiptRequested(),
+			canBeReceipted:        d.GetCanDocumentBeReceipted(),
+		}
+	}
+	if d := docflow.GetInboundInvoiceDocflow(); d != nil {
+		return legacyFlags{
+			known:        true,
+			isFinished:   d.GetIsFinished(),
+			isUnilateral: true,
+		}
+	}
+	if d := docflow.GetOutboundInvoiceDocflow(); d != nil {
+		return legacyFlags{
+			known:               true,
+			isFinished:          d.GetIsFinished(),
+			isUnilateral:        true,
+			canBeSignedBySender: d.GetCanDocumentBeSignedBySender(),
+		}
+	}
+	return legacyFlags{}
+}
+
+func removeActions(actions []Action, remove ...Action) []Action {
+	var result []Action
+	for _, a := range actions {
+		keep := true
+		for _, r := range remove {
+			if a == r {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			result = append(result, a)
+		}
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package docflowstate
+
+import (
+	"github.com/DimaSSV/diadocclient/pkg/model"
+)
+
+// Transition - изменение состояния документа между двумя снимками
+type Transition struct {
+	From Snapshot
+	To   Snapshot
+}
+
+// StateChanged сообщает, изменился ли этап жизненного цикла
+func (t Transition) StateChanged() bool {
+	return t.From.State != t.To.State
+}
+
+// Changed сообщает, изменились ли этап, необходимость извещения или завершенность документооборота
+func (t Transition) Changed() bool {
+	return t.StateChanged() ||
+		t.From.ReceiptRequired != t.To.ReceiptRequired ||
+		t.From.IsFinished != t.To.IsFinished
+}
+
+// NewActions возвращает действия, которые стали доступны после перехода
+func (t Transition) NewActions() []Action {
+	var result []Action
+	for _, a := range t.To.Actions {
+		if !t.From.Can(a) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// Compare сравнивает два снимка состояния одного документа
+func Compare(from Snapshot, to Snapshot) Transition {
+	return Transition{From: from, To: to}
+}
+
+// FromEventV3 вычисляет переход по событию GetDocflowEvents_V3. Предыдущее состояние
+// заполняется Диадоком только при PopulatePreviousDocumentStates = true; без него From имеет этап Unknown
+func FromEventV3(event *model.DocflowEventV3) Transition {
+	var from Snapshot
+	if event.GetPreviousDocumentState() != nil {
+		from = FromDocumentV3(event.GetPreviousDocumentState())
+	}
+	return Compare(from, FromDocumentV3(event.GetDocument()))
+}
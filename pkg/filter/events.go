@@ -0,0 +1,40 @@
+package filter
+
+import (
+	"errors"
+	"github.com/DimaSSV/diadocclient/pkg/ticks"
+)
+
+// Events параметры метода GetNewEvents. Нулевые значения полей означают отсутствие фильтра
+type Events struct {
+	AfterIndexKey      string
+	DepartmentID       string
+	MessageTypes       []MessageType
+	TypeNamedIDs       []string
+	DocumentDirections []Direction
+	TimestampFrom      ticks.Ticks
+	TimestampTo        ticks.Ticks
+	CounteragentBoxID  string
+	OrderBy            SortDirection
+	Limit              int
+}
+
+func (e Events) Validate() error {
+	for _, messageType := range e.MessageTypes {
+		if err := messageType.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, direction := range e.DocumentDirections {
+		if err := direction.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := e.OrderBy.Validate(); err != nil {
+		return err
+	}
+	if !e.TimestampFrom.IsZero() && !e.TimestampTo.IsZero() && e.TimestampTo < e.TimestampFrom {
+		return errors.New("начало интервала событий позже его окончания")
+	}
+	return validateLimit(e.Limit)
+}
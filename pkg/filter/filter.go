@@ -0,0 +1,203 @@
+// Package filter содержит типизированные значения параметров фильтрации документов и событий:
+// категории фильтра (тип документа × статус), типы сообщений, направления и порядок сортировки
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DocumentType тип документа в категории фильтра: TypeNamedId или Any
+type DocumentType string
+
+const (
+	AnyDocumentType             DocumentType = "Any"
+	Nonformalized               DocumentType = "Nonformalized"
+	Invoice                     DocumentType = "Invoice"
+	UniversalTransferDocument   DocumentType = "UniversalTransferDocument"
+	UniversalCorrectionDocument DocumentType = "UniversalCorrectionDocument"
+	XmlTorg12                   DocumentType = "XmlTorg12"
+	XmlAcceptanceCertificate    DocumentType = "XmlAcceptanceCertificate"
+	Contract                    DocumentType = "Contract"
+	SupplementaryAgreement      DocumentType = "SupplementaryAgreement"
+	ReconciliationAct           DocumentType = "ReconciliationAct"
+	ProformaInvoice             DocumentType = "ProformaInvoice"
+	PriceList                   DocumentType = "PriceList"
+	ServiceDetails              DocumentType = "ServiceDetails"
+)
+
+// Status статус документа в категории фильтра
+type Status string
+
+const (
+	Inbound                                  Status = "Inbound"
+	InboundNotRead                           Status = "InboundNotRead"
+	InboundNotFinished                       Status = "InboundNotFinished"
+	InboundFinished                          Status = "InboundFinished"
+	InboundWaitingForRecipientSignature      Status = "InboundWaitingForRecipientSignature"
+	InboundWithRecipientSignature            Status = "InboundWithRecipientSignature"
+	InboundRecipientSignatureRequestRejected Status = "InboundRecipientSignatureRequestRejected"
+	InboundInvalidRecipientSignature         Status = "InboundInvalidRecipientSignature"
+	InboundNoRecipientSignatureRequest       Status = "InboundNoRecipientSignatureRequest"
+	InboundRevocationIsRequestedByMe         Status = "InboundRevocationIsRequestedByMe"
+	InboundRequestsMyRevocation              Status = "InboundRequestsMyRevocation"
+	InboundRevocationAccepted                Status = "InboundRevocationAccepted"
+	InboundRevocationRejected                Status = "InboundRevocationRejected"
+	InboundNotRevoked                        Status = "InboundNotRevoked"
+	InboundHaveToCreateReceipt               Status = "InboundHaveToCreateReceipt"
+
+	Outbound                                  Status = "Outbound"
+	OutboundNotRead                           Status = "OutboundNotRead"
+	OutboundNotFinished                       Status = "OutboundNotFinished"
+	OutboundFinished                          Status = "OutboundFinished"
+	OutboundWaitingForSenderSignature         Status = "OutboundWaitingForSenderSignature"
+	OutboundInvalidSenderSignature            Status = "OutboundInvalidSenderSignature"
+	OutboundWaitingForRecipientSignature      Status = "OutboundWaitingForRecipientSignature"
+	OutboundWithRecipientSignature            Status = "OutboundWithRecipientSignature"
+	OutboundRecipientSignatureRequestRejected Status = "OutboundRecipientSignatureRequestRejected"
+	OutboundInvalidRecipientSignature         Status = "OutboundInvalidRecipientSignature"
+	OutboundNoRecipientSignatureRequest       Status = "OutboundNoRecipientSignatureRequest"
+	OutboundRevocationIsRequestedByMe         Status = "OutboundRevocationIsRequestedByMe"
+	OutboundRequestsMyRevocation              Status = "OutboundRequestsMyRevocation"
+	OutboundRevocationAccepted                Status = "OutboundRevocationAccepted"
+	OutboundRevocationRejected                Status = "OutboundRevocationRejected"
+	OutboundNotRevoked                        Status = "OutboundNotRevoked"
+
+	Internal                                  Status = "Internal"
+	InternalNotRead                           Status = "InternalNotRead"
+	InternalNotFinished                       Status = "InternalNotFinished"
+	InternalFinished                          Status = "InternalFinished"
+	InternalWaitingForSenderSignature         Status = "InternalWaitingForSenderSignature"
+	InternalWaitingForRecipientSignature      Status = "InternalWaitingForRecipientSignature"
+	InternalWithRecipientSignature            Status = "InternalWithRecipientSignature"
+	InternalRecipientSignatureRequestRejected Status = "InternalRecipientSignatureRequestRejected"
+	InternalRevocationIsRequestedByMe         Status = "InternalRevocationIsRequestedByMe"
+	InternalRevocationAccepted                Status = "InternalRevocationAccepted"
+	InternalRevocationRejected                Status = "InternalRevocationRejected"
+	InternalNotRevoked                        Status = "InternalNotRevoked"
+
+	Deleted Status = "Deleted"
+	Draft   Status = "Draft"
+)
+
+// unilateralTypes типы документов без подписи получателя: статусы, связанные с запросом подписи получателя,
+// для них не имеют смысла
+var unilateralTypes = map[DocumentType]bool{
+	Invoice: true,
+}
+
+// recipientSignatureStatuses статусы запроса подписи получателя (без учета направления)
+var recipientSignatureStatuses = map[string]bool{
+	"WaitingForRecipientSignature":      true,
+	"WithRecipientSignature":            true,
+	"RecipientSignatureRequestRejected": true,
+	"InvalidRecipientSignature":         true,
+}
+
+// externalOnlyTypes типы документов, которые не передаются внутри организации
+var externalOnlyTypes = map[DocumentType]bool{
+	Invoice: true,
+}
+
+// Category категория фильтра документов вида "<тип документа>.<статус>", например Any.Inbound
+type Category string
+
+func NewCategory(documentType DocumentType, status Status) Category {
+	return Category(string(documentType) + "." + string(status))
+}
+
+func (c Category) DocumentType() DocumentType {
+	documentType, _, _ := strings.Cut(string(c), ".")
+	return DocumentType(documentType)
+}
+
+func (c Category) Status() Status {
+	_, status, _ := strings.Cut(string(c), ".")
+	return Status(status)
+}
+
+// Validate проверяет вид категории и сочетание типа документа со статусом. Список статусов не ограничивается
+// константами пакета: Диадок поддерживает и другие статусы (согласование, запросы уточнения и т.п.)
+func (c Category) Validate() error {
+	documentType, status, ok := strings.Cut(string(c), ".")
+	if !ok || documentType == "" || status == "" || strings.Contains(status, ".") || strings.ContainsAny(string(c), " \t") {
+		return fmt.Errorf("категория фильтра %q должна иметь вид <тип документа>.<статус>", string(c))
+	}
+	direction, state := splitStatus(Status(status))
+	if direction == "Internal" && externalOnlyTypes[DocumentType(documentType)] {
+		return fmt.Errorf("документы типа %s не бывают внутренними, статус %s недопустим", documentType, status)
+	}
+	if unilateralTypes[DocumentType(documentType)] && recipientSignatureStatuses[state] {
+		return fmt.Errorf("документы типа %s не требуют подписи получателя, статус %s недопустим", documentType, status)
+	}
+	return nil
+}
+
+// splitStatus отделяет от статуса направление документа (Inbound, Outbound, Internal)
+func splitStatus(status Status) (string, string) {
+	for _, direction := range []string{"Inbound", "Outbound", "Internal"} {
+		if strings.HasPrefix(string(status), direction) {
+			return direction, strings.TrimPrefix(string(status), direction)
+		}
+	}
+	return "", string(status)
+}
+
+// MessageType тип сообщения в параметре messageType
+type MessageType string
+
+const (
+	MessageLetter   MessageType = "Letter"
+	MessageDraft    MessageType = "Draft"
+	MessageTemplate MessageType = "Template"
+)
+
+func (t MessageType) Validate() error {
+	switch t {
+	case MessageLetter, MessageDraft, MessageTemplate:
+		return nil
+	}
+	return fmt.Errorf("неизвестный тип сообщения %q", string(t))
+}
+
+// Direction направление документа в параметре documentDirection
+type Direction string
+
+const (
+	DirectionInbound  Direction = "Inbound"
+	DirectionOutbound Direction = "Outbound"
+	DirectionInternal Direction = "Internal"
+)
+
+func (d Direction) Validate() error {
+	switch d {
+	case DirectionInbound, DirectionOutbound, DirectionInternal:
+		return nil
+	}
+	return fmt.Errorf("неизвестное направление документа %q", string(d))
+}
+
+// SortDirection порядок сортировки. Пустое значение означает порядок по умолчанию
+type SortDirection string
+
+const (
+	Ascending  SortDirection = "Ascending"
+	Descending SortDirection = "Descending"
+)
+
+func (s SortDirection) Validate() error {
+	switch s {
+	case "", Ascending, Descending:
+		return nil
+	}
+	return fmt.Errorf("неизвестный порядок сортировки %q", string(s))
+}
+
+// validateLimit проверяет размер страницы (0 - значение по умолчанию)
+func validateLimit(limit int) error {
+	if limit < 0 || limit > 100 {
+		return errors.New("размер страницы должен быть от 1 до 100")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package filter
+
+import (
+	"testing"
+)
+
+func TestCategoryValidate(t *testing.T) {
+	tests := []struct {
+		category Category
+		valid    bool
+	}{
+		{NewCategory(AnyDocumentType, Inbound), true},
+		{NewCategory(Nonformalized, OutboundWaitingForRecipientSignature), true},
+		{NewCategory(UniversalTransferDocument, InternalWithRecipientSignature), true},
+		{NewCategory(Invoice, InboundNotRead), true},
+		{NewCategory(Invoice, OutboundNoRecipientSignatureRequest), true},
+		// Статусы, для которых нет констант, передаются в Диадок без изменений
+		{"Any.InboundApprovementRequested", true},
+		{"Invoice.OutboundAmendmentRequested", true},
+		{"SomeCustomTypeNamedId.Inbound", true},
+
+		{"Inbound", false},
+		{"Any.", false},
+		{".Inbound", false},
+		{"Any.Inbound.NotRead", false},
+		{"Any. Inbound", false},
+		{NewCategory(Invoice, InboundWaitingForRecipientSignature), false},
+		{NewCategory(Invoice, OutboundWithRecipientSignature), false},
+		{NewCategory(Invoice, InboundRecipientSignatureRequestRejected), false},
+		{NewCategory(Invoice, OutboundInvalidRecipientSignature), false},
+		{NewCategory(Invoice, InternalNotRead), false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.category), func(t *testing.T) {
+			err := tt.category.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Validate() = nil, want error")
+			}
+		})
+	}
+}
+
+func TestCategoryParts(t *testing.T) {
+	category := NewCategory(XmlTorg12, OutboundFinished)
+	if category != "XmlTorg12.OutboundFinished" {
+		t.Fatalf("NewCategory() = %q", category)
+	}
+	if category.DocumentType() != XmlTorg12 || category.Status() != OutboundFinished {
+		t.Errorf("DocumentType(), Status() = %q, %q", category.DocumentType(), category.Status())
+	}
+}
@@ -0,0 +1,657 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.21.9
+// source: DocumentsToSign.proto
+
+package model
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PrepareDocumentsToSignRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BoxId          *string                 `protobuf:"bytes,1,req,name=BoxId" json:"BoxId,omitempty"`
+	DraftDocuments []*DraftDocumentToPatch `protobuf:"bytes,2,rep,name=DraftDocuments" json:"DraftDocuments,omitempty"`
+	Documents      []*DocumentToPatch      `protobuf:"bytes,3,rep,name=Documents" json:"Documents,omitempty"`
+	Contents       []*ContentToPatch       `protobuf:"bytes,4,rep,name=Contents" json:"Contents,omitempty"`
+}
+
+func (x *PrepareDocumentsToSignRequest) Reset() {
+	*x = PrepareDocumentsToSignRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DocumentsToSign_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrepareDocumentsToSignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrepareDocumentsToSignRequest) ProtoMessage() {}
+
+func (x *PrepareDocumentsToSignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_DocumentsToSign_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrepareDocumentsToSignRequest.ProtoReflect.Descriptor instead.
+func (*PrepareDocumentsToSignRequest) Descriptor() ([]byte, []int) {
+	return file_DocumentsToSign_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PrepareDocumentsToSignRequest) GetBoxId() string {
+	if x != nil && x.BoxId != nil {
+		return *x.BoxId
+	}
+	return ""
+}
+
+func (x *PrepareDocumentsToSignRequest) GetDraftDocuments() []*DraftDocumentToPatch {
+	if x != nil {
+		return x.DraftDocuments
+	}
+	return nil
+}
+
+func (x *PrepareDocumentsToSignRequest) GetDocuments() []*DocumentToPatch {
+	if x != nil {
+		return x.Documents
+	}
+	return nil
+}
+
+func (x *PrepareDocumentsToSignRequest) GetContents() []*ContentToPatch {
+	if x != nil {
+		return x.Contents
+	}
+	return nil
+}
+
+type DraftDocumentToPatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DocumentId     *DocumentId       `protobuf:"bytes,1,req,name=DocumentId" json:"DocumentId,omitempty"`
+	ToBoxId        *string           `protobuf:"bytes,2,opt,name=ToBoxId" json:"ToBoxId,omitempty"`
+	Signer         *Signer           `protobuf:"bytes,3,opt,name=Signer" json:"Signer,omitempty"`
+	ExtendedSigner []*ExtendedSigner `protobuf:"bytes,4,rep,name=ExtendedSigner" json:"ExtendedSigner,omitempty"`
+}
+
+func (x *DraftDocumentToPatch) Reset() {
+	*x = DraftDocumentToPatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DocumentsToSign_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DraftDocumentToPatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DraftDocumentToPatch) ProtoMessage() {}
+
+func (x *DraftDocumentToPatch) ProtoReflect() protoreflect.Message {
+	mi := &file_DocumentsToSign_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DraftDocumentToPatch.ProtoReflect.Descriptor instead.
+func (*DraftDocumentToPatch) Descriptor() ([]byte, []int) {
+	return file_DocumentsToSign_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DraftDocumentToPatch) GetDocumentId() *DocumentId {
+	if x != nil {
+		return x.DocumentId
+	}
+	return nil
+}
+
+func (x *DraftDocumentToPatch) GetToBoxId() string {
+	if x != nil && x.ToBoxId != nil {
+		return *x.ToBoxId
+	}
+	return ""
+}
+
+func (x *DraftDocumentToPatch) GetSigner() *Signer {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+func (x *DraftDocumentToPatch) GetExtendedSigner() []*ExtendedSigner {
+	if x != nil {
+		return x.ExtendedSigner
+	}
+	return nil
+}
+
+type DocumentToPatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DocumentId     *DocumentId       `protobuf:"bytes,1,req,name=DocumentId" json:"DocumentId,omitempty"`
+	Signer         *Signer           `protobuf:"bytes,2,opt,name=Signer" json:"Signer,omitempty"`
+	ExtendedSigner []*ExtendedSigner `protobuf:"bytes,3,rep,name=ExtendedSigner" json:"ExtendedSigner,omitempty"`
+}
+
+func (x *DocumentToPatch) Reset() {
+	*x = DocumentToPatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DocumentsToSign_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DocumentToPatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentToPatch) ProtoMessage() {}
+
+func (x *DocumentToPatch) ProtoReflect() protoreflect.Message {
+	mi := &file_DocumentsToSign_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentToPatch.ProtoReflect.Descriptor instead.
+func (*DocumentToPatch) Descriptor() ([]byte, []int) {
+	return file_DocumentsToSign_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DocumentToPatch) GetDocumentId() *DocumentId {
+	if x != nil {
+		return x.DocumentId
+	}
+	return nil
+}
+
+func (x *DocumentToPatch) GetSigner() *Signer {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+func (x *DocumentToPatch) GetExtendedSigner() []*ExtendedSigner {
+	if x != nil {
+		return x.ExtendedSigner
+	}
+	return nil
+}
+
+type ContentToPatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TypeNamedId    *string           `protobuf:"bytes,1,req,name=TypeNamedId" json:"TypeNamedId,omitempty"`
+	Function       *string           `protobuf:"bytes,2,req,name=Function" json:"Function,omitempty"`
+	Version        *string           `protobuf:"bytes,3,req,name=Version" json:"Version,omitempty"`
+	Content        *UnsignedContent  `protobuf:"bytes,4,req,name=Content" json:"Content,omitempty"`
+	ToBoxId        *string           `protobuf:"bytes,5,req,name=ToBoxId" json:"ToBoxId,omitempty"`
+	Signer         *Signer           `protobuf:"bytes,6,opt,name=Signer" json:"Signer,omitempty"`
+	ExtendedSigner []*ExtendedSigner `protobuf:"bytes,7,rep,name=ExtendedSigner" json:"ExtendedSigner,omitempty"`
+}
+
+func (x *ContentToPatch) Reset() {
+	*x = ContentToPatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DocumentsToSign_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ContentToPatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContentToPatch) ProtoMessage() {}
+
+func (x *ContentToPatch) ProtoReflect() protoreflect.Message {
+	mi := &file_DocumentsToSign_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContentToPatch.ProtoReflect.Descriptor instead.
+func (*ContentToPatch) Descriptor() ([]byte, []int) {
+	return file_DocumentsToSign_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ContentToPatch) GetTypeNamedId() string {
+	if x != nil && x.TypeNamedId != nil {
+		return *x.TypeNamedId
+	}
+	return ""
+}
+
+func (x *ContentToPatch) GetFunction() string {
+	if x != nil && x.Function != nil {
+		return *x.Function
+	}
+	return ""
+}
+
+func (x *ContentToPatch) GetVersion() string {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return ""
+}
+
+func (x *ContentToPatch) GetContent() *UnsignedContent {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *ContentToPatch) GetToBoxId() string {
+	if x != nil && x.ToBoxId != nil {
+		return *x.ToBoxId
+	}
+	return ""
+}
+
+func (x *ContentToPatch) GetSigner() *Signer {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+func (x *ContentToPatch) GetExtendedSigner() []*ExtendedSigner {
+	if x != nil {
+		return x.ExtendedSigner
+	}
+	return nil
+}
+
+type PrepareDocumentsToSignResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DocumentPatchedContents []*DocumentPatchedContent `protobuf:"bytes,1,rep,name=DocumentPatchedContents" json:"DocumentPatchedContents,omitempty"`
+}
+
+func (x *PrepareDocumentsToSignResponse) Reset() {
+	*x = PrepareDocumentsToSignResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DocumentsToSign_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrepareDocumentsToSignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrepareDocumentsToSignResponse) ProtoMessage() {}
+
+func (x *PrepareDocumentsToSignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_DocumentsToSign_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrepareDocumentsToSignResponse.ProtoReflect.Descriptor instead.
+func (*PrepareDocumentsToSignResponse) Descriptor() ([]byte, []int) {
+	return file_DocumentsToSign_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PrepareDocumentsToSignResponse) GetDocumentPatchedContents() []*DocumentPatchedContent {
+	if x != nil {
+		return x.DocumentPatchedContents
+	}
+	return nil
+}
+
+type DocumentPatchedContent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DocumentId       *DocumentId `protobuf:"bytes,1,req,name=DocumentId" json:"DocumentId,omitempty"`
+	PatchedContentId *string     `protobuf:"bytes,2,req,name=PatchedContentId" json:"PatchedContentId,omitempty"`
+	Content          []byte      `protobuf:"bytes,3,opt,name=Content" json:"Content,omitempty"`
+}
+
+func (x *DocumentPatchedContent) Reset() {
+	*x = DocumentPatchedContent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DocumentsToSign_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DocumentPatchedContent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentPatchedContent) ProtoMessage() {}
+
+func (x *DocumentPatchedContent) ProtoReflect() protoreflect.Message {
+	mi := &file_DocumentsToSign_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentPatchedContent.ProtoReflect.Descriptor instead.
+func (*DocumentPatchedContent) Descriptor() ([]byte, []int) {
+	return file_DocumentsToSign_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DocumentPatchedContent) GetDocumentId() *DocumentId {
+	if x != nil {
+		return x.DocumentId
+	}
+	return nil
+}
+
+func (x *DocumentPatchedContent) GetPatchedContentId() string {
+	if x != nil && x.PatchedContentId != nil {
+		return *x.PatchedContentId
+	}
+	return ""
+}
+
+func (x *DocumentPatchedContent) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+var File_DocumentsToSign_proto protoreflect.FileDescriptor
+
+var file_DocumentsToSign_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x54, 0x6f, 0x53, 0x69, 0x67,
+	0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0a, 0x46, 0x75, 0x6c, 0x6c, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x0f, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd1, 0x01, 0x0a, 0x1d, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65,
+	0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x54, 0x6f, 0x53, 0x69, 0x67, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x42, 0x6f, 0x78, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x05, 0x42, 0x6f, 0x78, 0x49, 0x64, 0x12, 0x3d, 0x0a, 0x0e,
+	0x44, 0x72, 0x61, 0x66, 0x74, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x44, 0x72, 0x61, 0x66, 0x74, 0x44, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x54, 0x6f, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x0e, 0x44, 0x72, 0x61,
+	0x66, 0x74, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x2e, 0x0a, 0x09, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x54, 0x6f, 0x50, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x09, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x2b, 0x0a, 0x08, 0x43,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x6f, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52, 0x08,
+	0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x22, 0xb7, 0x01, 0x0a, 0x14, 0x44, 0x72, 0x61,
+	0x66, 0x74, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x54, 0x6f, 0x50, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x2b, 0x0a, 0x0a, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x02, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x52, 0x0a, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x18,
+	0x0a, 0x07, 0x54, 0x6f, 0x42, 0x6f, 0x78, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x54, 0x6f, 0x42, 0x6f, 0x78, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e,
+	0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65,
+	0x72, 0x52, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x37, 0x0a, 0x0e, 0x45, 0x78, 0x74,
+	0x65, 0x6e, 0x64, 0x65, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x53, 0x69, 0x67, 0x6e,
+	0x65, 0x72, 0x52, 0x0e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x53, 0x69, 0x67, 0x6e,
+	0x65, 0x72, 0x22, 0x98, 0x01, 0x0a, 0x0f, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x54,
+	0x6f, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x2b, 0x0a, 0x0a, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65,
+	0x6e, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x02, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x44, 0x6f, 0x63,
+	0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x52, 0x0a, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e,
+	0x74, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x06, 0x53, 0x69,
+	0x67, 0x6e, 0x65, 0x72, 0x12, 0x37, 0x0a, 0x0e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64,
+	0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x45,
+	0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x0e, 0x45,
+	0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x22, 0x88, 0x02,
+	0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x6f, 0x50, 0x61, 0x74, 0x63, 0x68,
+	0x12, 0x20, 0x0a, 0x0b, 0x54, 0x79, 0x70, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x0b, 0x54, 0x79, 0x70, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x64,
+	0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x02, 0x28, 0x09, 0x52, 0x08, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18,
+	0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x02, 0x28, 0x09, 0x52,
+	0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x02, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x55, 0x6e, 0x73, 0x69,
+	0x67, 0x6e, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x43, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x54, 0x6f, 0x42, 0x6f, 0x78, 0x49, 0x64, 0x18,
+	0x05, 0x20, 0x02, 0x28, 0x09, 0x52, 0x07, 0x54, 0x6f, 0x42, 0x6f, 0x78, 0x49, 0x64, 0x12, 0x1f,
+	0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x07,
+	0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12,
+	0x37, 0x0a, 0x0e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x65,
+	0x72, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64,
+	0x65, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x0e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64,
+	0x65, 0x64, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x22, 0x73, 0x0a, 0x1e, 0x50, 0x72, 0x65, 0x70,
+	0x61, 0x72, 0x65, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x54, 0x6f, 0x53, 0x69,
+	0x67, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x17, 0x44, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x43, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x44, 0x6f,
+	0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x43, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x52, 0x17, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x61,
+	0x74, 0x63, 0x68, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x8b, 0x01,
+	0x0a, 0x16, 0x44, 0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x74, 0x63, 0x68, 0x65,
+	0x64, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x0a, 0x44, 0x6f, 0x63, 0x75,
+	0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x02, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x44,
+	0x6f, 0x63, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x52, 0x0a, 0x44, 0x6f, 0x63, 0x75, 0x6d,
+	0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x10, 0x50, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64,
+	0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x02, 0x20, 0x02, 0x28, 0x09, 0x52,
+	0x10, 0x50, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x49,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x42, 0x10, 0x5a, 0x0e, 0x64,
+	0x69, 0x61, 0x64, 0x6f, 0x63, 0x65, 0x72, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x32,
+}
+
+var (
+	file_DocumentsToSign_proto_rawDescOnce sync.Once
+	file_DocumentsToSign_proto_rawDescData = file_DocumentsToSign_proto_rawDesc
+)
+
+func file_DocumentsToSign_proto_rawDescGZIP() []byte {
+	file_DocumentsToSign_proto_rawDescOnce.Do(func() {
+		file_DocumentsToSign_proto_rawDescData = protoimpl.X.CompressGZIP(file_DocumentsToSign_proto_rawDescData)
+	})
+	return file_DocumentsToSign_proto_rawDescData
+}
+
+var file_DocumentsToSign_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_DocumentsToSign_proto_goTypes = []interface{}{
+	(*PrepareDocumentsToSignRequest)(nil),  // 0: PrepareDocumentsToSignRequest
+	(*DraftDocumentToPatch)(nil),           // 1: DraftDocumentToPatch
+	(*DocumentToPatch)(nil),                // 2: DocumentToPatch
+	(*ContentToPatch)(nil),                 // 3: ContentToPatch
+	(*PrepareDocumentsToSignResponse)(nil), // 4: PrepareDocumentsToSignResponse
+	(*DocumentPatchedContent)(nil),         // 5: DocumentPatchedContent
+	(*DocumentId)(nil),                     // 6: DocumentId
+	(*Signer)(nil),                         // 7: Signer
+	(*ExtendedSigner)(nil),                 // 8: ExtendedSigner
+	(*UnsignedContent)(nil),                // 9: UnsignedContent
+}
+var file_DocumentsToSign_proto_depIdxs = []int32{
+	1,  // 0: PrepareDocumentsToSignRequest.DraftDocuments:type_name -> DraftDocumentToPatch
+	2,  // 1: PrepareDocumentsToSignRequest.Documents:type_name -> DocumentToPatch
+	3,  // 2: PrepareDocumentsToSignRequest.Contents:type_name -> ContentToPatch
+	6,  // 3: DraftDocumentToPatch.DocumentId:type_name -> DocumentId
+	7,  // 4: DraftDocumentToPatch.Signer:type_name -> Signer
+	8,  // 5: DraftDocumentToPatch.ExtendedSigner:type_name -> ExtendedSigner
+	6,  // 6: DocumentToPatch.DocumentId:type_name -> DocumentId
+	7,  // 7: DocumentToPatch.Signer:type_name -> Signer
+	8,  // 8: DocumentToPatch.ExtendedSigner:type_name -> ExtendedSigner
+	9,  // 9: ContentToPatch.Content:type_name -> UnsignedContent
+	7,  // 10: ContentToPatch.Signer:type_name -> Signer
+	8,  // 11: ContentToPatch.ExtendedSigner:type_name -> ExtendedSigner
+	5,  // 12: PrepareDocumentsToSignResponse.DocumentPatchedContents:type_name -> DocumentPatchedContent
+	6,  // 13: DocumentPatchedContent.DocumentId:type_name -> DocumentId
+	14, // [14:14] is the sub-list for method output_type
+	14, // [14:14] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_DocumentsToSign_proto_init() }
+func file_DocumentsToSign_proto_init() {
+	if File_DocumentsToSign_proto != nil {
+		return
+	}
+	file_Full_proto_init()
+	file_Invoicing_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_DocumentsToSign_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrepareDocumentsToSignRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DocumentsToSign_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DraftDocumentToPatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DocumentsToSign_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DocumentToPatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DocumentsToSign_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ContentToPatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DocumentsToSign_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrepareDocumentsToSignResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DocumentsToSign_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DocumentPatchedContent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_DocumentsToSign_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_DocumentsToSign_proto_goTypes,
+		DependencyIndexes: file_DocumentsToSign_proto_depIdxs,
+		MessageInfos:      file_DocumentsToSign_proto_msgTypes,
+	}.Build()
+	File_DocumentsToSign_proto = out.File
+	file_DocumentsToSign_proto_rawDesc = nil
+	file_DocumentsToSign_proto_goTypes = nil
+	file_DocumentsToSign_proto_depIdxs = nil
+}
@@ -0,0 +1,507 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.21.9
+// source: Invoicing.proto
+
+package model
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Signer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SignerCertificate           []byte         `protobuf:"bytes,1,opt,name=SignerCertificate" json:"SignerCertificate,omitempty"`
+	SignerDetails               *SignerDetails `protobuf:"bytes,2,opt,name=SignerDetails" json:"SignerDetails,omitempty"`
+	SignerCertificateThumbprint *string        `protobuf:"bytes,3,opt,name=SignerCertificateThumbprint" json:"SignerCertificateThumbprint,omitempty"`
+}
+
+func (x *Signer) Reset() {
+	*x = Signer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_Invoicing_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Signer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Signer) ProtoMessage() {}
+
+func (x *Signer) ProtoReflect() protoreflect.Message {
+	mi := &file_Invoicing_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Signer.ProtoReflect.Descriptor instead.
+func (*Signer) Descriptor() ([]byte, []int) {
+	return file_Invoicing_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Signer) GetSignerCertificate() []byte {
+	if x != nil {
+		return x.SignerCertificate
+	}
+	return nil
+}
+
+func (x *Signer) GetSignerDetails() *SignerDetails {
+	if x != nil {
+		return x.SignerDetails
+	}
+	return nil
+}
+
+func (x *Signer) GetSignerCertificateThumbprint() string {
+	if x != nil && x.SignerCertificateThumbprint != nil {
+		return *x.SignerCertificateThumbprint
+	}
+	return ""
+}
+
+type SignerDetails struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Surname                               *string `protobuf:"bytes,1,req,name=Surname" json:"Surname,omitempty"`
+	FirstName                             *string `protobuf:"bytes,2,req,name=FirstName" json:"FirstName,omitempty"`
+	Patronymic                            *string `protobuf:"bytes,3,opt,name=Patronymic" json:"Patronymic,omitempty"`
+	JobTitle                              *string `protobuf:"bytes,4,opt,name=JobTitle" json:"JobTitle,omitempty"`
+	Inn                                   *string `protobuf:"bytes,5,opt,name=Inn" json:"Inn,omitempty"`
+	SoleProprietorRegistrationCertificate *string `protobuf:"bytes,6,opt,name=SoleProprietorRegistrationCertificate" json:"SoleProprietorRegistrationCertificate,omitempty"`
+}
+
+func (x *SignerDetails) Reset() {
+	*x = SignerDetails{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_Invoicing_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignerDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignerDetails) ProtoMessage() {}
+
+func (x *SignerDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_Invoicing_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignerDetails.ProtoReflect.Descriptor instead.
+func (*SignerDetails) Descriptor() ([]byte, []int) {
+	return file_Invoicing_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SignerDetails) GetSurname() string {
+	if x != nil && x.Surname != nil {
+		return *x.Surname
+	}
+	return ""
+}
+
+func (x *SignerDetails) GetFirstName() string {
+	if x != nil && x.FirstName != nil {
+		return *x.FirstName
+	}
+	return ""
+}
+
+func (x *SignerDetails) GetPatronymic() string {
+	if x != nil && x.Patronymic != nil {
+		return *x.Patronymic
+	}
+	return ""
+}
+
+func (x *SignerDetails) GetJobTitle() string {
+	if x != nil && x.JobTitle != nil {
+		return *x.JobTitle
+	}
+	return ""
+}
+
+func (x *SignerDetails) GetInn() string {
+	if x != nil && x.Inn != nil {
+		return *x.Inn
+	}
+	return ""
+}
+
+func (x *SignerDetails) GetSoleProprietorRegistrationCertificate() string {
+	if x != nil && x.SoleProprietorRegistrationCertificate != nil {
+		return *x.SoleProprietorRegistrationCertificate
+	}
+	return ""
+}
+
+type SignatureRejectionInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrorMessage *string `protobuf:"bytes,1,opt,name=ErrorMessage" json:"ErrorMessage,omitempty"` // Причина отказа в подписи
+	Signer       *Signer `protobuf:"bytes,2,req,name=Signer" json:"Signer,omitempty"`
+}
+
+func (x *SignatureRejectionInfo) Reset() {
+	*x = SignatureRejectionInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_Invoicing_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignatureRejectionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignatureRejectionInfo) ProtoMessage() {}
+
+func (x *SignatureRejectionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_Invoicing_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignatureRejectionInfo.ProtoReflect.Descriptor instead.
+func (*SignatureRejectionInfo) Descriptor() ([]byte, []int) {
+	return file_Invoicing_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SignatureRejectionInfo) GetErrorMessage() string {
+	if x != nil && x.ErrorMessage != nil {
+		return *x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *SignatureRejectionInfo) GetSigner() *Signer {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+type RevocationRequestInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Comment *string `protobuf:"bytes,1,opt,name=Comment" json:"Comment,omitempty"` // Причина аннулирования
+	Signer  *Signer `protobuf:"bytes,2,req,name=Signer" json:"Signer,omitempty"`
+}
+
+func (x *RevocationRequestInfo) Reset() {
+	*x = RevocationRequestInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_Invoicing_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevocationRequestInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevocationRequestInfo) ProtoMessage() {}
+
+func (x *RevocationRequestInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_Invoicing_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevocationRequestInfo.ProtoReflect.Descriptor instead.
+func (*RevocationRequestInfo) Descriptor() ([]byte, []int) {
+	return file_Invoicing_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RevocationRequestInfo) GetComment() string {
+	if x != nil && x.Comment != nil {
+		return *x.Comment
+	}
+	return ""
+}
+
+func (x *RevocationRequestInfo) GetSigner() *Signer {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+type InvoiceCorrectionRequestInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Signer       *Signer `protobuf:"bytes,1,req,name=Signer" json:"Signer,omitempty"`
+	ErrorMessage *string `protobuf:"bytes,2,req,name=ErrorMessage" json:"ErrorMessage,omitempty"` // Текст уведомления об уточнении
+}
+
+func (x *InvoiceCorrectionRequestInfo) Reset() {
+	*x = InvoiceCorrectionRequestInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_Invoicing_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InvoiceCorrectionRequestInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvoiceCorrectionRequestInfo) ProtoMessage() {}
+
+func (x *InvoiceCorrectionRequestInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_Invoicing_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvoiceCorrectionRequestInfo.ProtoReflect.Descriptor instead.
+func (*InvoiceCorrectionRequestInfo) Descriptor() ([]byte, []int) {
+	return file_Invoicing_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *InvoiceCorrectionRequestInfo) GetSigner() *Signer {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+func (x *InvoiceCorrectionRequestInfo) GetErrorMessage() string {
+	if x != nil && x.ErrorMessage != nil {
+		return *x.ErrorMessage
+	}
+	return ""
+}
+
+var File_Invoicing_proto protoreflect.FileDescriptor
+
+var file_Invoicing_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0xae, 0x01, 0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x12, 0x2c, 0x0a, 0x11,
+	0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x43,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x34, 0x0a, 0x0d, 0x53, 0x69,
+	0x67, 0x6e, 0x65, 0x72, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0e, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c,
+	0x73, 0x52, 0x0d, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73,
+	0x12, 0x40, 0x0a, 0x1b, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x54, 0x68, 0x75, 0x6d, 0x62, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x1b, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x43, 0x65, 0x72,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x54, 0x68, 0x75, 0x6d, 0x62, 0x70, 0x72, 0x69,
+	0x6e, 0x74, 0x22, 0xeb, 0x01, 0x0a, 0x0d, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x44, 0x65, 0x74,
+	0x61, 0x69, 0x6c, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x75, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x02, 0x28, 0x09, 0x52, 0x07, 0x53, 0x75, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x46, 0x69, 0x72, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x02, 0x28,
+	0x09, 0x52, 0x09, 0x46, 0x69, 0x72, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
+	0x50, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x79, 0x6d, 0x69, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x50, 0x61, 0x74, 0x72, 0x6f, 0x6e, 0x79, 0x6d, 0x69, 0x63, 0x12, 0x1a, 0x0a, 0x08,
+	0x4a, 0x6f, 0x62, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x4a, 0x6f, 0x62, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x49, 0x6e, 0x6e, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x49, 0x6e, 0x6e, 0x12, 0x54, 0x0a, 0x25, 0x53, 0x6f,
+	0x6c, 0x65, 0x50, 0x72, 0x6f, 0x70, 0x72, 0x69, 0x65, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x67, 0x69,
+	0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x25, 0x53, 0x6f, 0x6c, 0x65, 0x50,
+	0x72, 0x6f, 0x70, 0x72, 0x69, 0x65, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x22, 0x5d, 0x0a, 0x16, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x6a,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x22, 0x0a, 0x0c, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1f,
+	0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x02, 0x20, 0x02, 0x28, 0x0b, 0x32, 0x07,
+	0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x22,
+	0x52, 0x0a, 0x15, 0x52, 0x65, 0x76, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d,
+	0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x65,
+	0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x02, 0x20, 0x02,
+	0x28, 0x0b, 0x32, 0x07, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x06, 0x53, 0x69, 0x67,
+	0x6e, 0x65, 0x72, 0x22, 0x63, 0x0a, 0x1c, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x43, 0x6f,
+	0x72, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49,
+	0x6e, 0x66, 0x6f, 0x12, 0x1f, 0x0a, 0x06, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x02, 0x28, 0x0b, 0x32, 0x07, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x72, 0x52, 0x06, 0x53, 0x69,
+	0x67, 0x6e, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x02, 0x28, 0x09, 0x52, 0x0c, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x10, 0x5a, 0x0e, 0x64, 0x69, 0x61, 0x64,
+	0x6f, 0x63, 0x65, 0x72, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x32,
+}
+
+var (
+	file_Invoicing_proto_rawDescOnce sync.Once
+	file_Invoicing_proto_rawDescData = file_Invoicing_proto_rawDesc
+)
+
+func file_Invoicing_proto_rawDescGZIP() []byte {
+	file_Invoicing_proto_rawDescOnce.Do(func() {
+		file_Invoicing_proto_rawDescData = protoimpl.X.CompressGZIP(file_Invoicing_proto_rawDescData)
+	})
+	return file_Invoicing_proto_rawDescData
+}
+
+var file_Invoicing_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_Invoicing_proto_goTypes = []interface{}{
+	(*Signer)(nil),                       // 0: Signer
+	(*SignerDetails)(nil),                // 1: SignerDetails
+	(*SignatureRejectionInfo)(nil),       // 2: SignatureRejectionInfo
+	(*RevocationRequestInfo)(nil),        // 3: RevocationRequestInfo
+	(*InvoiceCorrectionRequestInfo)(nil), // 4: InvoiceCorrectionRequestInfo
+}
+var file_Invoicing_proto_depIdxs = []int32{
+	1, // 0: Signer.SignerDetails:type_name -> SignerDetails
+	0, // 1: SignatureRejectionInfo.Signer:type_name -> Signer
+	0, // 2: RevocationRequestInfo.Signer:type_name -> Signer
+	0, // 3: InvoiceCorrectionRequestInfo.Signer:type_name -> Signer
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_Invoicing_proto_init() }
+func file_Invoicing_proto_init() {
+	if File_Invoicing_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_Invoicing_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Signer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_Invoicing_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignerDetails); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_Invoicing_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignatureRejectionInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_Invoicing_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevocationRequestInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_Invoicing_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InvoiceCorrectionRequestInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_Invoicing_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_Invoicing_proto_goTypes,
+		DependencyIndexes: file_Invoicing_proto_depIdxs,
+		MessageInfos:      file_Invoicing_proto_msgTypes,
+	}.Build()
+	File_Invoicing_proto = out.File
+	file_Invoicing_proto_rawDesc = nil
+	file_Invoicing_proto_goTypes = nil
+	file_Invoicing_proto_depIdxs = nil
+}
@@ -0,0 +1,333 @@
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"github.com/DimaSSV/diadocclient/internal/cms"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	oidINN    = asn1.ObjectIdentifier{1, 2, 643, 3, 131, 1, 1}
+	oidINNLE  = asn1.ObjectIdentifier{1, 2, 643, 100, 4}
+	oidOGRN   = asn1.ObjectIdentifier{1, 2, 643, 100, 1}
+	oidOGRNIP = asn1.ObjectIdentifier{1, 2, 643, 100, 5}
+	oidSNILS  = asn1.ObjectIdentifier{1, 2, 643, 100, 3}
+
+	oidSurname    = asn1.ObjectIdentifier{2, 5, 4, 4}
+	oidGivenName  = asn1.ObjectIdentifier{2, 5, 4, 42}
+	oidTitle      = asn1.ObjectIdentifier{2, 5, 4, 12}
+	oidEmail      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}
+	oidStreet     = asn1.ObjectIdentifier{2, 5, 4, 9}
+	oidCommonName = asn1.ObjectIdentifier{2, 5, 4, 3}
+)
+
+// Известные алгоритмы хэширования
+var (
+	OIDGOST3411_94       = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 9}
+	OIDGOST3411_2012_256 = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 2, 2}
+	OIDGOST3411_2012_512 = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 2, 3}
+	OIDSHA1              = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	OIDSHA256            = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	OIDSHA384            = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	OIDSHA512            = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+)
+
+var digestAlgorithmNames = map[string]string{
+	OIDGOST3411_94.String():       "ГОСТ Р 34.11-94",
+	OIDGOST3411_2012_256.String(): "ГОСТ Р 34.11-2012 (256 бит)",
+	OIDGOST3411_2012_512.String(): "ГОСТ Р 34.11-2012 (512 бит)",
+	OIDSHA1.String():              "SHA-1",
+	OIDSHA256.String():            "SHA-256",
+	OIDSHA384.String():            "SHA-384",
+	OIDSHA512.String():            "SHA-512",
+}
+
+var (
+	ErrNoMessageDigest   = errors.New("signature: в подписи нет подписанного атрибута messageDigest")
+	ErrDigestMismatch    = errors.New("signature: хэш содержимого не совпадает с messageDigest подписи")
+	ErrUnsupportedDigest = errors.New("signature: алгоритм хэширования не поддерживается")
+)
+
+// HashProvider вычисляет хэш данных по OID алгоритма. Для подписей ГОСТ
+// реализация подключается снаружи (криптопровайдер или программная библиотека)
+type HashProvider interface {
+	Hash(algorithm asn1.ObjectIdentifier, data []byte) ([]byte, error)
+}
+
+type HashProviderFunc func(algorithm asn1.ObjectIdentifier, data []byte) ([]byte, error)
+
+func (f HashProviderFunc) Hash(algorithm asn1.ObjectIdentifier, data []byte) ([]byte, error) {
+	return f(algorithm, data)
+}
+
+// StdHashProvider вычисляет SHA-1 и SHA-2 средствами стандартной библиотеки
+var StdHashProvider HashProvider = HashProviderFunc(func(algorithm asn1.ObjectIdentifier, data []byte) ([]byte, error) {
+	var hash crypto.Hash
+	switch {
+	case algorithm.Equal(OIDSHA1):
+		hash = crypto.SHA1
+	case algorithm.Equal(OIDSHA256):
+		hash = crypto.SHA256
+	case algorithm.Equal(OIDSHA384):
+		hash = crypto.SHA384
+	case algorithm.Equal(OIDSHA512):
+		hash = crypto.SHA512
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDigest, algorithm)
+	}
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil), nil
+})
+
+// Signature сведения об открепленной подписи CMS
+type Signature struct {
+	Signers      []Signer
+	Certificates [][]byte
+}
+
+// Signer сведения об одном подписанте. Криптографическая проверка подписи не выполняется
+type Signer struct {
+	Subject      pkix.Name
+	Issuer       pkix.Name
+	SerialNumber *big.Int
+	NotBefore    time.Time
+	NotAfter     time.Time
+
+	Surname    string
+	GivenName  string
+	Title      string
+	Email      string
+	INN        string
+	INNLE      string
+	OGRN       string
+	OGRNIP     string
+	SNILS      string
+	Street     string
+	CommonName string
+
+	SigningTime        time.Time
+	DigestAlgorithm    asn1.ObjectIdentifier
+	SignatureAlgorithm asn1.ObjectIdentifier
+	MessageDigest      []byte
+	Certificate        []byte
+}
+
+// DigestAlgorithmName возвращает название алгоритма хэширования или OID, если алгоритм неизвестен
+func (s Signer) DigestAlgorithmName() string {
+	if name, ok := digestAlgorithmNames[s.DigestAlgorithm.String()]; ok {
+		return name
+	}
+	return s.DigestAlgorithm.String()
+}
+
+// HasCertificate сообщает, найден ли сертификат подписанта в подписи
+func (s Signer) HasCertificate() bool {
+	return s.Certificate != nil
+}
+
+// VerifyDigest сверяет атрибут messageDigest с хэшем content
+func (s Signer) VerifyDigest(content []byte, provider HashProvider) error {
+	if s.MessageDigest == nil {
+		return ErrNoMessageDigest
+	}
+	digest, err := provider.Hash(s.DigestAlgorithm, content)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(digest, s.MessageDigest) {
+		return ErrDigestMismatch
+	}
+	return nil
+}
+
+// VerifyDigest сверяет messageDigest всех подписантов с хэшем content
+func (s *Signature) VerifyDigest(content []byte, provider HashProvider) error {
+	if len(s.Signers) == 0 {
+		return errors.New("signature: в подписи нет подписантов")
+	}
+	for _, signer := range s.Signers {
+		if err := signer.VerifyDigest(content, provider); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Parse разбирает открепленную подпись CMS, например полученную через GetEntityContent
+func Parse(data []byte) (*Signature, error) {
+	signedData, err := cms.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	rawCertificates, err := signedData.RawCertificates()
+	if err != nil {
+		return nil, err
+	}
+	certificates := make([]certificate, 0, len(rawCertificates))
+	for _, raw := range rawCertificates {
+		cert, err := parseCertificate(raw)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, cert)
+	}
+	result := Signature{Certificates: rawCertificates}
+	for _, info := range signedData.SignerInfos {
+		signer := Signer{
+			DigestAlgorithm:    info.DigestAlgorithm.Algorithm,
+			SignatureAlgorithm: info.SignatureAlgorithm.Algorithm,
+		}
+		attributes, err := info.Attributes()
+		if err != nil {
+			return nil, err
+		}
+		for _, attr := range attributes {
+			switch {
+			case attr.Type.Equal(cms.OIDMessageDigest):
+				if _, err = asn1.Unmarshal(attr.Values.Bytes, &signer.MessageDigest); err != nil {
+					return nil, err
+				}
+			case attr.Type.Equal(cms.OIDSigningTime):
+				if _, err = asn1.Unmarshal(attr.Values.Bytes, &signer.SigningTime); err != nil {
+					return nil, err
+				}
+			}
+		}
+		cert, ok := findCertificate(certificates, info.SID)
+		if !ok && len(certificates) == 1 {
+			cert, ok = certificates[0], true
+		}
+		if ok {
+			cert.fill(&signer)
+		}
+		result.Signers = append(result.Signers, signer)
+	}
+	return &result, nil
+}
+
+type certificate struct {
+	raw          []byte
+	tbs          tbsCertificate
+	subject      pkix.RDNSequence
+	issuer       pkix.RDNSequence
+	subjectKeyID []byte
+}
+
+type tbsCertificate struct {
+	Version            int `asn1:"optional,explicit,default:0,tag:0"`
+	SerialNumber       *big.Int
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Issuer             asn1.RawValue
+	Validity           validity
+	Subject            asn1.RawValue
+	PublicKey          asn1.RawValue
+	IssuerUniqueID     asn1.BitString   `asn1:"optional,tag:1"`
+	SubjectUniqueID    asn1.BitString   `asn1:"optional,tag:2"`
+	Extensions         []pkix.Extension `asn1:"optional,explicit,tag:3"`
+}
+
+type validity struct {
+	NotBefore, NotAfter time.Time
+}
+
+var oidSubjectKeyID = asn1.ObjectIdentifier{2, 5, 29, 14}
+
+// parseCertificate разбирает сертификат без проверки алгоритма открытого ключа,
+// чтобы поддерживать сертификаты ГОСТ
+func parseCertificate(raw []byte) (certificate, error) {
+	var outer struct {
+		TBS       asn1.RawValue
+		Algorithm pkix.AlgorithmIdentifier
+		Signature asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(raw, &outer); err != nil {
+		return certificate{}, err
+	}
+	cert := certificate{raw: raw}
+	if _, err := asn1.Unmarshal(outer.TBS.FullBytes, &cert.tbs); err != nil {
+		return certificate{}, err
+	}
+	if _, err := asn1.Unmarshal(cert.tbs.Subject.FullBytes, &cert.subject); err != nil {
+		return certificate{}, err
+	}
+	if _, err := asn1.Unmarshal(cert.tbs.Issuer.FullBytes, &cert.issuer); err != nil {
+		return certificate{}, err
+	}
+	for _, ext := range cert.tbs.Extensions {
+		if ext.Id.Equal(oidSubjectKeyID) {
+			_, _ = asn1.Unmarshal(ext.Value, &cert.subjectKeyID)
+		}
+	}
+	return cert, nil
+}
+
+func findCertificate(certificates []certificate, sid asn1.RawValue) (certificate, bool) {
+	if sid.Class == asn1.ClassContextSpecific && sid.Tag == 0 {
+		for _, cert := range certificates {
+			if cert.subjectKeyID != nil && bytes.Equal(cert.subjectKeyID, sid.Bytes) {
+				return cert, true
+			}
+		}
+		return certificate{}, false
+	}
+	var issuerAndSerial cms.IssuerAndSerialNumber
+	if _, err := asn1.Unmarshal(sid.FullBytes, &issuerAndSerial); err != nil {
+		return certificate{}, false
+	}
+	for _, cert := range certificates {
+		if cert.tbs.SerialNumber.Cmp(issuerAndSerial.SerialNumber) == 0 &&
+			bytes.Equal(cert.tbs.Issuer.FullBytes, issuerAndSerial.Issuer.FullBytes) {
+			return cert, true
+		}
+	}
+	return certificate{}, false
+}
+
+func (c certificate) fill(s *Signer) {
+	s.Certificate = c.raw
+	s.SerialNumber = c.tbs.SerialNumber
+	s.NotBefore = c.tbs.Validity.NotBefore
+	s.NotAfter = c.tbs.Validity.NotAfter
+	s.Subject.FillFromRDNSequence(&c.subject)
+	s.Issuer.FillFromRDNSequence(&c.issuer)
+	for _, rdn := range c.subject {
+		for _, atv := range rdn {
+			value := strings.TrimSpace(fmt.Sprint(atv.Value))
+			switch {
+			case atv.Type.Equal(oidINN):
+				s.INN = value
+			case atv.Type.Equal(oidINNLE):
+				s.INNLE = value
+			case atv.Type.Equal(oidOGRN):
+				s.OGRN = value
+			case atv.Type.Equal(oidOGRNIP):
+				s.OGRNIP = value
+			case atv.Type.Equal(oidSNILS):
+				s.SNILS = value
+			case atv.Type.Equal(oidSurname):
+				s.Surname = value
+			case atv.Type.Equal(oidGivenName):
+				s.GivenName = value
+			case atv.Type.Equal(oidTitle):
+				s.Title = value
+			case atv.Type.Equal(oidEmail):
+				s.Email = value
+			case atv.Type.Equal(oidStreet):
+				s.Street = value
+			case atv.Type.Equal(oidCommonName):
+				s.CommonName = value
+			}
+		}
+	}
+}
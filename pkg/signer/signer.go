@@ -0,0 +1,14 @@
+package signer
+
+import (
+	"context"
+	"crypto/x509"
+)
+
+// Signer формирует открепленную подпись CMS (PKCS#7) для передачи в Диадок.
+type Signer interface {
+	// Certificate возвращает сертификат подписанта
+	Certificate() *x509.Certificate
+	// Sign возвращает открепленную подпись CMS для data
+	Sign(ctx context.Context, data []byte) ([]byte, error)
+}
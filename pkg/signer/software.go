@@ -0,0 +1,69 @@
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"github.com/DimaSSV/diadocclient/internal/cms"
+	"math/big"
+	"time"
+)
+
+// SoftwareSigner формирует подписи ключами RSA или ECDSA средствами стандартной библиотеки.
+// Диадок в рабочем контуре принимает только подписи ГОСТ, поэтому SoftwareSigner
+// предназначен для тестов и отладки цепочек обработки документов
+type SoftwareSigner struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+func NewSoftwareSigner(cert *x509.Certificate, key crypto.Signer) *SoftwareSigner {
+	return &SoftwareSigner{
+		cert: cert,
+		key:  key,
+	}
+}
+
+// NewTestSigner создает SoftwareSigner с новым ключом ECDSA P-256 и самоподписанным сертификатом
+func NewTestSigner(commonName string) (*SoftwareSigner, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return NewSoftwareSigner(cert, key), nil
+}
+
+func (s *SoftwareSigner) Certificate() *x509.Certificate {
+	return s.cert
+}
+
+func (s *SoftwareSigner) Sign(ctx context.Context, data []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return cms.SignDetached(data, s.cert, s.key, time.Now())
+}
@@ -0,0 +1,72 @@
+// Package ticks переводит время Диадока в тиках .NET (интервалы по 100 нс, прошедшие с 0001-01-01 00:00:00 UTC)
+// в time.Time и model.Timestamp и обратно. В тиках передаются параметры timestampFromTicks/timestampToTicks,
+// поля *Ticks моделей (например, Counteragent.LastEventTimestampTicks, см. LastEvent) и model.Timestamp
+package ticks
+
+import (
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"google.golang.org/protobuf/proto"
+	"strconv"
+	"time"
+)
+
+// Ticks время в тиках .NET. Нулевое значение соответствует нулевому time.Time и означает "не задано"
+type Ticks int64
+
+const (
+	PerSecond = Ticks(10000000)
+	// unixEpoch значение на 1970-01-01 00:00:00 UTC
+	unixEpoch = Ticks(621355968000000000)
+)
+
+func FromTime(t time.Time) Ticks {
+	if t.IsZero() {
+		return 0
+	}
+	return Ticks(t.Unix())*PerSecond + Ticks(t.Nanosecond()/100) + unixEpoch
+}
+
+// FromTimestamp возвращает 0 для nil
+func FromTimestamp(timestamp *model.Timestamp) Ticks {
+	return Ticks(timestamp.GetTicks())
+}
+
+// LastEvent возвращает время последнего события контрагента (Counteragent.LastEventTimestampTicks), 0 для nil
+func LastEvent(counteragent *model.Counteragent) Ticks {
+	return Ticks(counteragent.GetLastEventTimestampTicks())
+}
+
+func Now() Ticks {
+	return FromTime(time.Now())
+}
+
+// Time возвращает время в UTC
+func (t Ticks) Time() time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	d := t - unixEpoch
+	sec, rem := d/PerSecond, d%PerSecond
+	if rem < 0 {
+		sec--
+		rem += PerSecond
+	}
+	return time.Unix(int64(sec), int64(rem)*100).UTC()
+}
+
+// Timestamp возвращает nil для нулевого значения
+func (t Ticks) Timestamp() *model.Timestamp {
+	if t == 0 {
+		return nil
+	}
+	return &model.Timestamp{Ticks: proto.Int64(int64(t))}
+}
+
+func (t Ticks) IsZero() bool {
+	return t == 0
+}
+
+// String возвращает десятичную запись, в которой тики передаются в параметрах запросов
+func (t Ticks) String() string {
+	return strconv.FormatInt(int64(t), 10)
+}
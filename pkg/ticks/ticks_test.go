@@ -0,0 +1,59 @@
+package ticks
+
+import (
+	"github.com/DimaSSV/diadocclient/pkg/model"
+	"google.golang.org/protobuf/proto"
+	"testing"
+	"time"
+)
+
+func TestConversion(t *testing.T) {
+	tests := []struct {
+		name  string
+		time  time.Time
+		ticks Ticks
+	}{
+		{"zero", time.Time{}, 0},
+		{"unix epoch", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), 621355968000000000},
+		{"2020-01-01", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 637134336000000000},
+		{"sub-second", time.Date(2020, 1, 1, 0, 0, 0, 1234567*100, time.UTC), 637134336001234567},
+		{"before epoch", time.Date(1969, 12, 31, 23, 59, 59, 500000000, time.UTC), 621355967995000000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromTime(tt.time); got != tt.ticks {
+				t.Errorf("FromTime(%v) = %d, want %d", tt.time, got, tt.ticks)
+			}
+			if got := tt.ticks.Time(); !got.Equal(tt.time) {
+				t.Errorf("Ticks(%d).Time() = %v, want %v", tt.ticks, got, tt.time)
+			}
+		})
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	if got := Ticks(0).Timestamp(); got != nil {
+		t.Errorf("Ticks(0).Timestamp() = %v, want nil", got)
+	}
+	if got := FromTimestamp(nil); got != 0 {
+		t.Errorf("FromTimestamp(nil) = %d, want 0", got)
+	}
+	const value = Ticks(637134336000000000)
+	if got := FromTimestamp(value.Timestamp()); got != value {
+		t.Errorf("FromTimestamp(Timestamp()) = %d, want %d", got, value)
+	}
+	if got := value.String(); got != "637134336000000000" {
+		t.Errorf("String() = %q, want %q", got, "637134336000000000")
+	}
+}
+
+func TestLastEvent(t *testing.T) {
+	if got := LastEvent(nil); got != 0 {
+		t.Errorf("LastEvent(nil) = %d, want 0", got)
+	}
+	counteragent := &model.Counteragent{LastEventTimestampTicks: proto.Int64(637134336000000000)}
+	want := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := LastEvent(counteragent).Time(); !got.Equal(want) {
+		t.Errorf("LastEvent().Time() = %v, want %v", got, want)
+	}
+}
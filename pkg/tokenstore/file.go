@@ -0,0 +1,132 @@
+package tokenstore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// File хранилище в JSON-файле: все токены в одном файле, доступном только владельцу (0600).
+// Запись выполняется через временный файл, чтобы при сбое не повредить сохраненные токены
+type File struct {
+	mu   sync.Mutex
+	path string
+	aead cipher.AEAD
+}
+
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+// NewEncryptedFile создает хранилище, содержимое которого шифруется AES-GCM.
+// Ключ должен иметь длину 16, 24 или 32 байта
+func NewEncryptedFile(path string, key []byte) (*File, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &File{path: path, aead: aead}, nil
+}
+
+func (f *File) Load(key string) (Token, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tokens, err := f.read()
+	if err != nil {
+		return Token{}, false, err
+	}
+	token, ok := tokens[key]
+	return token, ok, nil
+}
+
+func (f *File) Save(key string, token Token) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tokens, err := f.read()
+	if err != nil {
+		return err
+	}
+	tokens[key] = token
+	return f.write(tokens)
+}
+
+func (f *File) Delete(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tokens, err := f.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[key]; !ok {
+		return nil
+	}
+	delete(tokens, key)
+	return f.write(tokens)
+}
+
+func (f *File) read() (map[string]Token, error) {
+	tokens := make(map[string]Token)
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if f.aead != nil {
+		size := f.aead.NonceSize()
+		if len(data) < size {
+			return nil, errors.New("tokenstore: поврежден зашифрованный файл токенов")
+		}
+		if data, err = f.aead.Open(nil, data[:size], data[size:], nil); err != nil {
+			return nil, errors.New("tokenstore: не удалось расшифровать файл токенов")
+		}
+	}
+	if err = json.Unmarshal(data, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (f *File) write(tokens map[string]Token) error {
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	if f.aead != nil {
+		nonce := make([]byte, f.aead.NonceSize())
+		if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+			return err
+		}
+		data = f.aead.Seal(nonce, nonce, data, nil)
+	}
+	dir := filepath.Dir(f.path)
+	if err = os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".tokens-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+// Package tokenstore содержит хранилища авторизационных токенов Диадока, позволяющие не выполнять
+// вход при каждом запуске процесса: в памяти, в файле и в зашифрованном файле
+package tokenstore
+
+import (
+	"sync"
+	"time"
+)
+
+// Token авторизационный токен и время его получения
+type Token struct {
+	Value    string    `json:"value"`
+	IssuedAt time.Time `json:"issuedAt"`
+}
+
+// Age возвращает время, прошедшее с получения токена
+func (t Token) Age() time.Duration {
+	return time.Since(t.IssuedAt)
+}
+
+// Store хранилище токенов. Ключ определяет учетную запись (логин и идентификатор клиента API).
+// Реализации должны быть безопасны для одновременного использования
+type Store interface {
+	// Load возвращает сохраненный токен; ok = false, если токена нет
+	Load(key string) (token Token, ok bool, err error)
+	Save(key string, token Token) error
+	Delete(key string) error
+}
+
+// Memory хранилище в памяти процесса. Полезно для нескольких клиентов одной учетной записи
+type Memory struct {
+	mu     sync.Mutex
+	tokens map[string]Token
+}
+
+func NewMemory() *Memory {
+	return &Memory{tokens: make(map[string]Token)}
+}
+
+func (m *Memory) Load(key string) (Token, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, ok := m.tokens[key]
+	return token, ok, nil
+}
+
+func (m *Memory) Save(key string, token Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[key] = token
+	return nil
+}
+
+func (m *Memory) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tokens, key)
+	return nil
+}